@@ -5,4 +5,8 @@ go 1.24.5
 require (
 	github.com/google/uuid v1.6.0 // direct
 	github.com/lib/pq v1.10.9 // direct
+	golang.org/x/net v0.40.0 // direct
+	golang.org/x/sys v0.33.0 // indirect
+	golang.org/x/term v0.32.0 // direct
+	golang.org/x/text v0.25.0 // indirect
 )
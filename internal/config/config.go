@@ -13,6 +13,25 @@ const gatorconfigjson string = ".gatorconfig.json"
 type Config struct {
 	Db_url           string `json:"db_url"`
 	Current_username string `json:"current_username"`
+	// settings for the http client used to fetch feeds
+	Fetch_timeout  string `json:"fetch_timeout,omitempty"`
+	Max_body_bytes int64  `json:"max_body_bytes,omitempty"`
+	User_agent     string `json:"user_agent,omitempty"`
+	Contact_url    string `json:"contact_url,omitempty"`
+	Proxy_url      string `json:"proxy_url,omitempty"`
+	Ca_bundle      string `json:"ca_bundle,omitempty"`
+	// bounds on how often agg polls a single feed
+	Min_fetch_interval string `json:"min_fetch_interval,omitempty"`
+	Max_fetch_interval string `json:"max_fetch_interval,omitempty"`
+	// feeds are disabled after this many failed fetches in a row
+	Max_consecutive_failures int32 `json:"max_consecutive_failures,omitempty"`
+	// websub push subscriptions, hubs call back on websub_callback_url
+	Websub_callback_url string `json:"websub_callback_url,omitempty"`
+	Websub_listen_addr  string `json:"websub_listen_addr,omitempty"`
+	// base64 aes-256 key that encrypts stored feed credentials
+	Credentials_key string `json:"credentials_key,omitempty"`
+	// commands exec:name feeds may run, name -> program and arguments
+	Exec_commands map[string][]string `json:"exec_commands,omitempty"`
 }
 
 func Read() Config {
@@ -47,11 +66,16 @@ func (c Config) SetUser(current_username string) error {
 	if err != nil {
 		return fmt.Errorf("Error marshaling config data")
 	}
-	//write to file
-	err = os.WriteFile(path, data, 0644)
+	//write to file, only readable by the user since it holds credentials_key
+	err = os.WriteFile(path, data, 0600)
 	if err != nil {
 		return fmt.Errorf("Error writing file")
 	}
+	// WriteFile keeps the mode of a file that already exists
+	err = os.Chmod(path, 0600)
+	if err != nil {
+		return fmt.Errorf("Error setting file permissions")
+	}
 	return nil
 }
 
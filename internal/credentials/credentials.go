@@ -0,0 +1,172 @@
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ErrNoKey is returned when credentials are used without a key in the config
+var ErrNoKey = errors.New("credentials_key is not set in the config")
+
+// Credentials authenticate requests for a private feed
+type Credentials struct {
+	Username string            `json:"username,omitempty"`
+	Password string            `json:"password,omitempty"`
+	Token    string            `json:"token,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Cookies  map[string]string `json:"cookies,omitempty"`
+}
+
+// Parse reads --auth values of the forms basic:user:password, bearer:token,
+// header:Name=value and cookie:name=value
+func Parse(specs []string) (Credentials, error) {
+	var c Credentials
+	for _, spec := range specs {
+		kind, value, ok := strings.Cut(spec, ":")
+		if !ok || value == "" {
+			// the value may hold a secret, keep it out of the error
+			return c, fmt.Errorf("invalid auth, expected kind:value")
+		}
+		switch strings.ToLower(kind) {
+		case "basic":
+			user, password, _ := strings.Cut(value, ":")
+			c.Username = user
+			c.Password = password
+		case "bearer":
+			c.Token = value
+		case "header":
+			name, headerValue, ok := strings.Cut(value, "=")
+			if !ok || strings.TrimSpace(name) == "" {
+				return c, fmt.Errorf("invalid header auth, expected header:Name=value")
+			}
+			if c.Headers == nil {
+				c.Headers = make(map[string]string)
+			}
+			c.Headers[http.CanonicalHeaderKey(strings.TrimSpace(name))] = headerValue
+		case "cookie":
+			name, cookieValue, ok := strings.Cut(value, "=")
+			if !ok || strings.TrimSpace(name) == "" {
+				return c, fmt.Errorf("invalid cookie auth, expected cookie:name=value")
+			}
+			if c.Cookies == nil {
+				c.Cookies = make(map[string]string)
+			}
+			c.Cookies[strings.TrimSpace(name)] = cookieValue
+		default:
+			return c, fmt.Errorf("unknown auth kind %q, expected basic, bearer, header or cookie", kind)
+		}
+	}
+	return c, nil
+}
+
+func (c Credentials) IsZero() bool {
+	return c.Username == "" && c.Password == "" && c.Token == "" && len(c.Headers) == 0 && len(c.Cookies) == 0
+}
+
+// Apply adds the credentials to a request
+func (c Credentials) Apply(req *http.Request) {
+	if c.Username != "" || c.Password != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	for name, value := range c.Headers {
+		req.Header.Set(name, value)
+	}
+	for name, value := range c.Cookies {
+		req.AddCookie(&http.Cookie{Name: name, Value: value})
+	}
+}
+
+// Describe names the kinds of credentials without revealing any of them
+func (c Credentials) Describe() string {
+	var kinds []string
+	if c.Username != "" || c.Password != "" {
+		kinds = append(kinds, "basic auth")
+	}
+	if c.Token != "" {
+		kinds = append(kinds, "bearer token")
+	}
+	var names []string
+	for name := range c.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		kinds = append(kinds, name+" header")
+	}
+	if len(c.Cookies) > 0 {
+		kinds = append(kinds, fmt.Sprintf("%d cookie(s)", len(c.Cookies)))
+	}
+	return strings.Join(kinds, ", ")
+}
+
+// ParseKey decodes the base64 credentials_key from the config, it has to be
+// 32 bytes for aes-256
+func ParseKey(encoded string) ([]byte, error) {
+	if encoded == "" {
+		return nil, ErrNoKey
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credentials_key: %v", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("invalid credentials_key: want 32 bytes, got %d", len(key))
+	}
+	return key, nil
+}
+
+// Seal encrypts credentials with aes-gcm, the nonce is stored in front of
+// the ciphertext
+func Seal(key []byte, c Credentials) ([]byte, error) {
+	plain, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plain, nil), nil
+}
+
+// Open decrypts credentials stored by Seal
+func Open(key, sealed []byte) (Credentials, error) {
+	var c Credentials
+	aead, err := newAEAD(key)
+	if err != nil {
+		return c, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return c, errors.New("stored credentials are truncated")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return c, errors.New("couldnt decrypt stored credentials, was credentials_key changed?")
+	}
+	err = json.Unmarshal(plain, &c)
+	return c, err
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,171 @@
+package credentials
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		specs []string
+		want  Credentials
+	}{
+		{"none", nil, Credentials{}},
+		{"basic", []string{"basic:ann:pa:ss"}, Credentials{Username: "ann", Password: "pa:ss"}},
+		{"basic without password", []string{"basic:ann"}, Credentials{Username: "ann"}},
+		{"bearer", []string{"BEARER:tok"}, Credentials{Token: "tok"}},
+		{"header", []string{"header: private-token =a=b"}, Credentials{Headers: map[string]string{"Private-Token": "a=b"}}},
+		{"cookie", []string{"cookie:session=abc", "cookie:theme="}, Credentials{Cookies: map[string]string{"session": "abc", "theme": ""}}},
+		{
+			"combined",
+			[]string{"bearer:tok", "header:X-Key=k"},
+			Credentials{Token: "tok", Headers: map[string]string{"X-Key": "k"}},
+		},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.specs)
+		if err != nil {
+			t.Errorf("%s: Parse: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Parse = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"hunter2", "invalid auth, expected kind:value"},
+		{"bearer:", "invalid auth, expected kind:value"},
+		{"header:hunter2", "invalid header auth"},
+		{"header: =hunter2", "invalid header auth"},
+		{"cookie:hunter2", "invalid cookie auth"},
+		{"digest:hunter2", `unknown auth kind "digest"`},
+	}
+	for _, tt := range tests {
+		_, err := Parse([]string{tt.spec})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%q) = %v, want %q", tt.spec, err, tt.want)
+			continue
+		}
+		if strings.Contains(err.Error(), "hunter2") {
+			t.Errorf("Parse(%q) error shows the secret: %v", tt.spec, err)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	c := Credentials{
+		Username: "ann",
+		Password: "secret",
+		Headers:  map[string]string{"Private-Token": "tok"},
+		Cookies:  map[string]string{"session": "abc"},
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com/feed", nil)
+	c.Apply(req)
+	if user, password, ok := req.BasicAuth(); !ok || user != "ann" || password != "secret" {
+		t.Errorf("basic auth = %q, %q, %v", user, password, ok)
+	}
+	if got := req.Header.Get("Private-Token"); got != "tok" {
+		t.Errorf("Private-Token = %q", got)
+	}
+	if cookie, err := req.Cookie("session"); err != nil || cookie.Value != "abc" {
+		t.Errorf("session cookie = %v, %v", cookie, err)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "https://example.com/feed", nil)
+	Credentials{Token: "tok"}.Apply(req)
+	if got := req.Header.Get("Authorization"); got != "Bearer tok" {
+		t.Errorf("Authorization = %q", got)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	c := Credentials{
+		Username: "ann",
+		Password: "pw-secret",
+		Token:    "tok-secret",
+		Headers:  map[string]string{"X-Key": "hdr-secret", "Private-Token": "hdr-secret"},
+		Cookies:  map[string]string{"session": "cookie-secret"},
+	}
+	got := c.Describe()
+	want := "basic auth, bearer token, Private-Token header, X-Key header, 1 cookie(s)"
+	if got != want {
+		t.Errorf("Describe = %q, want %q", got, want)
+	}
+	for _, secret := range []string{"ann", "secret", "session"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("Describe shows %q: %s", secret, got)
+		}
+	}
+	if got := (Credentials{}).Describe(); got != "" {
+		t.Errorf("Describe of no credentials = %q", got)
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	got, err := ParseKey(base64.StdEncoding.EncodeToString(key))
+	if err != nil || !bytes.Equal(got, key) {
+		t.Errorf("ParseKey = %v, %v", got, err)
+	}
+	if _, err := ParseKey(""); !errors.Is(err, ErrNoKey) {
+		t.Errorf("ParseKey(\"\") = %v, want ErrNoKey", err)
+	}
+	if _, err := ParseKey("not base64!"); err == nil {
+		t.Error("ParseKey accepted a key that is not base64")
+	}
+	if _, err := ParseKey(base64.StdEncoding.EncodeToString(key[:16])); err == nil {
+		t.Error("ParseKey accepted a 16 byte key")
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	c := Credentials{Token: "tok-secret", Cookies: map[string]string{"session": "abc"}}
+	sealed, err := Seal(key, c)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if bytes.Contains(sealed, []byte("tok-secret")) {
+		t.Error("sealed credentials hold the plain token")
+	}
+	again, err := Seal(key, c)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if bytes.Equal(sealed, again) {
+		t.Error("sealing twice gave the same bytes, the nonce is not random")
+	}
+
+	got, err := Open(key, sealed)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if !reflect.DeepEqual(got, c) {
+		t.Errorf("Open = %+v, want %+v", got, c)
+	}
+
+	if _, err := Open(bytes.Repeat([]byte{2}, 32), sealed); err == nil || !strings.Contains(err.Error(), "credentials_key changed") {
+		t.Errorf("Open with the wrong key = %v", err)
+	}
+	if _, err := Open(key, sealed[:5]); err == nil || !strings.Contains(err.Error(), "truncated") {
+		t.Errorf("Open of truncated data = %v", err)
+	}
+	if _, err := Open(key, sealed[:len(sealed)-1]); err == nil {
+		t.Error("Open accepted data missing its last byte")
+	}
+	if _, err := Open(key[:10], sealed); err == nil {
+		t.Error("Open accepted a 10 byte key")
+	}
+}
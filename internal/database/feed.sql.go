@@ -7,11 +7,41 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const addFeedFetchedItems = `-- name: AddFeedFetchedItems :exec
+UPDATE feed SET fetch_count=fetch_count+1, item_count=item_count+$2 WHERE id=$1
+`
+
+type AddFeedFetchedItemsParams struct {
+	ID        uuid.UUID
+	ItemCount int32
+}
+
+func (q *Queries) AddFeedFetchedItems(ctx context.Context, arg AddFeedFetchedItemsParams) error {
+	_, err := q.db.ExecContext(ctx, addFeedFetchedItems, arg.ID, arg.ItemCount)
+	return err
+}
+
+const addFeedNewPosts = `-- name: AddFeedNewPosts :exec
+UPDATE feed SET new_post_count=new_post_count+$2, last_post_at=now() WHERE id=$1
+`
+
+type AddFeedNewPostsParams struct {
+	ID           uuid.UUID
+	NewPostCount int32
+}
+
+func (q *Queries) AddFeedNewPosts(ctx context.Context, arg AddFeedNewPostsParams) error {
+	_, err := q.db.ExecContext(ctx, addFeedNewPosts, arg.ID, arg.NewPostCount)
+	return err
+}
+
 const createFeed = `-- name: CreateFeed :one
 INSERT INTO feed (id,created_at,updated_at,name,url,user_id) VALUES(
     $1,
@@ -21,7 +51,7 @@ INSERT INTO feed (id,created_at,updated_at,name,url,user_id) VALUES(
     $5,
     $6
 ) 
-RETURNING id, created_at, updated_at, name, url, user_id, lastfetched_at
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, etag, last_modified, consecutive_failures, last_error, last_status, next_fetch_at, fetch_full_text, channel_title, channel_description, site_link, language, image_url, generator, last_build_date, update_interval_seconds, skip_hours, skip_days, credentials, disabled_at, fetch_count, new_post_count, item_count, last_post_at
 `
 
 type CreateFeedParams struct {
@@ -50,7 +80,30 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		&i.Name,
 		&i.Url,
 		&i.UserID,
-		&i.LastfetchedAt,
+		&i.LastFetchedAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.ConsecutiveFailures,
+		&i.LastError,
+		&i.LastStatus,
+		&i.NextFetchAt,
+		&i.FetchFullText,
+		&i.ChannelTitle,
+		&i.ChannelDescription,
+		&i.SiteLink,
+		&i.Language,
+		&i.ImageUrl,
+		&i.Generator,
+		&i.LastBuildDate,
+		&i.UpdateIntervalSeconds,
+		pq.Array(&i.SkipHours),
+		pq.Array(&i.SkipDays),
+		&i.Credentials,
+		&i.DisabledAt,
+		&i.FetchCount,
+		&i.NewPostCount,
+		&i.ItemCount,
+		&i.LastPostAt,
 	)
 	return i, err
 }
@@ -115,6 +168,29 @@ func (q *Queries) CreateFeedFollow(ctx context.Context, arg CreateFeedFollowPara
 	return i, err
 }
 
+const createFeedURLHistory = `-- name: CreateFeedURLHistory :exec
+INSERT INTO feed_url_history (id, created_at, feed_id, url)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (url) DO NOTHING
+`
+
+type CreateFeedURLHistoryParams struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	FeedID    uuid.UUID
+	Url       string
+}
+
+func (q *Queries) CreateFeedURLHistory(ctx context.Context, arg CreateFeedURLHistoryParams) error {
+	_, err := q.db.ExecContext(ctx, createFeedURLHistory,
+		arg.ID,
+		arg.CreatedAt,
+		arg.FeedID,
+		arg.Url,
+	)
+	return err
+}
+
 const deleteFeedFollowByUserAndURL = `-- name: DeleteFeedFollowByUserAndURL :exec
 
 DELETE FROM feedfollows
@@ -122,7 +198,8 @@ USING users, feed
 WHERE feedfollows.user_id = users.id
   AND feedfollows.feed_id = feed.id
   AND users.name = $1
-  AND feed.url = $2
+  AND (feed.url = $2
+    OR feed.id IN (SELECT feed_url_history.feed_id FROM feed_url_history WHERE feed_url_history.url = $2))
 `
 
 type DeleteFeedFollowByUserAndURLParams struct {
@@ -135,6 +212,115 @@ func (q *Queries) DeleteFeedFollowByUserAndURL(ctx context.Context, arg DeleteFe
 	return err
 }
 
+const disableFeed = `-- name: DisableFeed :exec
+UPDATE feed SET disabled_at=now(), updated_at=now() WHERE id=$1
+`
+
+func (q *Queries) DisableFeed(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, disableFeed, id)
+	return err
+}
+
+const enableFeed = `-- name: EnableFeed :execrows
+UPDATE feed SET disabled_at=NULL, consecutive_failures=0, next_fetch_at=NULL, updated_at=now() WHERE url=$1
+`
+
+func (q *Queries) EnableFeed(ctx context.Context, url string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, enableFeed, url)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getFeedById = `-- name: GetFeedById :one
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, etag, last_modified, consecutive_failures, last_error, last_status, next_fetch_at, fetch_full_text, channel_title, channel_description, site_link, language, image_url, generator, last_build_date, update_interval_seconds, skip_hours, skip_days, credentials, disabled_at, fetch_count, new_post_count, item_count, last_post_at FROM feed
+WHERE id=$1
+`
+
+func (q *Queries) GetFeedById(ctx context.Context, id uuid.UUID) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, getFeedById, id)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.ConsecutiveFailures,
+		&i.LastError,
+		&i.LastStatus,
+		&i.NextFetchAt,
+		&i.FetchFullText,
+		&i.ChannelTitle,
+		&i.ChannelDescription,
+		&i.SiteLink,
+		&i.Language,
+		&i.ImageUrl,
+		&i.Generator,
+		&i.LastBuildDate,
+		&i.UpdateIntervalSeconds,
+		pq.Array(&i.SkipHours),
+		pq.Array(&i.SkipDays),
+		&i.Credentials,
+		&i.DisabledAt,
+		&i.FetchCount,
+		&i.NewPostCount,
+		&i.ItemCount,
+		&i.LastPostAt,
+	)
+	return i, err
+}
+
+const getFeedByUrl = `-- name: GetFeedByUrl :one
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, etag, last_modified, consecutive_failures, last_error, last_status, next_fetch_at, fetch_full_text, channel_title, channel_description, site_link, language, image_url, generator, last_build_date, update_interval_seconds, skip_hours, skip_days, credentials, disabled_at, fetch_count, new_post_count, item_count, last_post_at FROM feed
+WHERE feed.url=$1
+   OR feed.id IN (SELECT feed_url_history.feed_id FROM feed_url_history WHERE feed_url_history.url=$1)
+LIMIT 1
+`
+
+func (q *Queries) GetFeedByUrl(ctx context.Context, url string) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, getFeedByUrl, url)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.ConsecutiveFailures,
+		&i.LastError,
+		&i.LastStatus,
+		&i.NextFetchAt,
+		&i.FetchFullText,
+		&i.ChannelTitle,
+		&i.ChannelDescription,
+		&i.SiteLink,
+		&i.Language,
+		&i.ImageUrl,
+		&i.Generator,
+		&i.LastBuildDate,
+		&i.UpdateIntervalSeconds,
+		pq.Array(&i.SkipHours),
+		pq.Array(&i.SkipDays),
+		&i.Credentials,
+		&i.DisabledAt,
+		&i.FetchCount,
+		&i.NewPostCount,
+		&i.ItemCount,
+		&i.LastPostAt,
+	)
+	return i, err
+}
+
 const getFeedFollowsForUser = `-- name: GetFeedFollowsForUser :many
 SELECT
     feedfollows.id,
@@ -191,8 +377,73 @@ func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) (
 	return items, nil
 }
 
+const getFeedHealth = `-- name: GetFeedHealth :many
+SELECT
+    f.id, f.name, f.url, f.last_fetched_at, f.last_status, f.last_error,
+    f.consecutive_failures, f.fetch_count, f.item_count, f.new_post_count,
+    f.last_post_at, f.next_fetch_at, f.disabled_at
+FROM feed f
+ORDER BY f.disabled_at IS NULL, f.consecutive_failures DESC, f.name
+`
+
+type GetFeedHealthRow struct {
+	ID                  uuid.UUID
+	Name                string
+	Url                 string
+	LastFetchedAt       sql.NullTime
+	LastStatus          sql.NullInt32
+	LastError           sql.NullString
+	ConsecutiveFailures int32
+	FetchCount          int32
+	ItemCount           int32
+	NewPostCount        int32
+	LastPostAt          sql.NullTime
+	NextFetchAt         sql.NullTime
+	DisabledAt          sql.NullTime
+}
+
+func (q *Queries) GetFeedHealth(ctx context.Context) ([]GetFeedHealthRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedHealth)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFeedHealthRow
+	for rows.Next() {
+		var i GetFeedHealthRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Url,
+			&i.LastFetchedAt,
+			&i.LastStatus,
+			&i.LastError,
+			&i.ConsecutiveFailures,
+			&i.FetchCount,
+			&i.ItemCount,
+			&i.NewPostCount,
+			&i.LastPostAt,
+			&i.NextFetchAt,
+			&i.DisabledAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getFeedIdFromUrl = `-- name: GetFeedIdFromUrl :one
-SELECT feed.id FROM feed WHERE url=$1
+SELECT feed.id FROM feed WHERE feed.url=$1
+UNION
+SELECT feed_url_history.feed_id FROM feed_url_history WHERE feed_url_history.url=$1
+LIMIT 1
 `
 
 func (q *Queries) GetFeedIdFromUrl(ctx context.Context, url string) (uuid.UUID, error) {
@@ -203,7 +454,11 @@ func (q *Queries) GetFeedIdFromUrl(ctx context.Context, url string) (uuid.UUID,
 }
 
 const getNextFeed = `-- name: GetNextFeed :one
-SELECT id, created_at, updated_at, name, url, user_id, lastfetched_at FROM feed ORDER BY lastfetched_at NULLS FIRST LIMIT 1
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, etag, last_modified, consecutive_failures, last_error, last_status, next_fetch_at, fetch_full_text, channel_title, channel_description, site_link, language, image_url, generator, last_build_date, update_interval_seconds, skip_hours, skip_days, credentials, disabled_at, fetch_count, new_post_count, item_count, last_post_at FROM feed
+WHERE disabled_at IS NULL
+  AND (next_fetch_at IS NULL OR next_fetch_at <= now())
+ORDER BY next_fetch_at NULLS FIRST, last_fetched_at NULLS FIRST
+LIMIT 1
 `
 
 func (q *Queries) GetNextFeed(ctx context.Context) (Feed, error) {
@@ -216,14 +471,73 @@ func (q *Queries) GetNextFeed(ctx context.Context) (Feed, error) {
 		&i.Name,
 		&i.Url,
 		&i.UserID,
-		&i.LastfetchedAt,
+		&i.LastFetchedAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.ConsecutiveFailures,
+		&i.LastError,
+		&i.LastStatus,
+		&i.NextFetchAt,
+		&i.FetchFullText,
+		&i.ChannelTitle,
+		&i.ChannelDescription,
+		&i.SiteLink,
+		&i.Language,
+		&i.ImageUrl,
+		&i.Generator,
+		&i.LastBuildDate,
+		&i.UpdateIntervalSeconds,
+		pq.Array(&i.SkipHours),
+		pq.Array(&i.SkipDays),
+		&i.Credentials,
+		&i.DisabledAt,
+		&i.FetchCount,
+		&i.NewPostCount,
+		&i.ItemCount,
+		&i.LastPostAt,
 	)
 	return i, err
 }
 
+const markFeedFetchFailed = `-- name: MarkFeedFetchFailed :exec
+UPDATE feed SET consecutive_failures=consecutive_failures+1, last_error=$2, last_status=$3, next_fetch_at=$4 WHERE id=$1
+`
+
+type MarkFeedFetchFailedParams struct {
+	ID          uuid.UUID
+	LastError   sql.NullString
+	LastStatus  sql.NullInt32
+	NextFetchAt sql.NullTime
+}
+
+func (q *Queries) MarkFeedFetchFailed(ctx context.Context, arg MarkFeedFetchFailedParams) error {
+	_, err := q.db.ExecContext(ctx, markFeedFetchFailed,
+		arg.ID,
+		arg.LastError,
+		arg.LastStatus,
+		arg.NextFetchAt,
+	)
+	return err
+}
+
+const markFeedFetchSucceeded = `-- name: MarkFeedFetchSucceeded :exec
+UPDATE feed SET consecutive_failures=0, last_error=NULL, last_status=$2, next_fetch_at=$3 WHERE id=$1
+`
+
+type MarkFeedFetchSucceededParams struct {
+	ID          uuid.UUID
+	LastStatus  sql.NullInt32
+	NextFetchAt sql.NullTime
+}
+
+func (q *Queries) MarkFeedFetchSucceeded(ctx context.Context, arg MarkFeedFetchSucceededParams) error {
+	_, err := q.db.ExecContext(ctx, markFeedFetchSucceeded, arg.ID, arg.LastStatus, arg.NextFetchAt)
+	return err
+}
+
 const markFetchedFeed = `-- name: MarkFetchedFeed :exec
 
-UPDATE feed SET lastfetched_at=now(), updated_at=now() WHERE id=$1
+UPDATE feed SET last_fetched_at=now(), updated_at=now() WHERE id=$1
 `
 
 func (q *Queries) MarkFetchedFeed(ctx context.Context, id uuid.UUID) error {
@@ -233,7 +547,9 @@ func (q *Queries) MarkFetchedFeed(ctx context.Context, id uuid.UUID) error {
 
 const returnAllFeedsWithUsers = `-- name: ReturnAllFeedsWithUsers :many
 SELECT 
-    f.id, f.created_at, f.updated_at, f.name, f.url, f.user_id, u.name AS username
+    f.id, f.created_at, f.updated_at, f.name, f.url, f.user_id,
+    f.channel_title, f.site_link, f.language,
+    f.credentials IS NOT NULL AS has_credentials, f.disabled_at, u.name AS username
 FROM 
     feed f
 JOIN 
@@ -241,13 +557,18 @@ JOIN
 `
 
 type ReturnAllFeedsWithUsersRow struct {
-	ID        uuid.UUID
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Name      string
-	Url       string
-	UserID    uuid.UUID
-	Username  string
+	ID             uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Name           string
+	Url            string
+	UserID         uuid.UUID
+	ChannelTitle   sql.NullString
+	SiteLink       sql.NullString
+	Language       sql.NullString
+	HasCredentials bool
+	DisabledAt     sql.NullTime
+	Username       string
 }
 
 func (q *Queries) ReturnAllFeedsWithUsers(ctx context.Context) ([]ReturnAllFeedsWithUsersRow, error) {
@@ -266,6 +587,11 @@ func (q *Queries) ReturnAllFeedsWithUsers(ctx context.Context) ([]ReturnAllFeeds
 			&i.Name,
 			&i.Url,
 			&i.UserID,
+			&i.ChannelTitle,
+			&i.SiteLink,
+			&i.Language,
+			&i.HasCredentials,
+			&i.DisabledAt,
 			&i.Username,
 		); err != nil {
 			return nil, err
@@ -280,3 +606,125 @@ func (q *Queries) ReturnAllFeedsWithUsers(ctx context.Context) ([]ReturnAllFeeds
 	}
 	return items, nil
 }
+
+const setFeedCredentials = `-- name: SetFeedCredentials :execrows
+UPDATE feed SET credentials=$2, updated_at=now() WHERE url=$1
+`
+
+type SetFeedCredentialsParams struct {
+	Url         string
+	Credentials []byte
+}
+
+func (q *Queries) SetFeedCredentials(ctx context.Context, arg SetFeedCredentialsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setFeedCredentials, arg.Url, arg.Credentials)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setFeedFetchFullText = `-- name: SetFeedFetchFullText :execrows
+UPDATE feed SET fetch_full_text=$2, updated_at=now() WHERE url=$1
+`
+
+type SetFeedFetchFullTextParams struct {
+	Url           string
+	FetchFullText bool
+}
+
+func (q *Queries) SetFeedFetchFullText(ctx context.Context, arg SetFeedFetchFullTextParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setFeedFetchFullText, arg.Url, arg.FetchFullText)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateFeedCacheHeaders = `-- name: UpdateFeedCacheHeaders :exec
+UPDATE feed SET etag=$2, last_modified=$3 WHERE id=$1
+`
+
+type UpdateFeedCacheHeadersParams struct {
+	ID           uuid.UUID
+	Etag         sql.NullString
+	LastModified sql.NullString
+}
+
+func (q *Queries) UpdateFeedCacheHeaders(ctx context.Context, arg UpdateFeedCacheHeadersParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedCacheHeaders, arg.ID, arg.Etag, arg.LastModified)
+	return err
+}
+
+const updateFeedMetadata = `-- name: UpdateFeedMetadata :exec
+UPDATE feed SET
+    channel_title=$2,
+    channel_description=$3,
+    site_link=$4,
+    language=$5,
+    image_url=$6,
+    generator=$7,
+    last_build_date=$8,
+    updated_at=now()
+WHERE id=$1
+`
+
+type UpdateFeedMetadataParams struct {
+	ID                 uuid.UUID
+	ChannelTitle       sql.NullString
+	ChannelDescription sql.NullString
+	SiteLink           sql.NullString
+	Language           sql.NullString
+	ImageUrl           sql.NullString
+	Generator          sql.NullString
+	LastBuildDate      sql.NullTime
+}
+
+func (q *Queries) UpdateFeedMetadata(ctx context.Context, arg UpdateFeedMetadataParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedMetadata,
+		arg.ID,
+		arg.ChannelTitle,
+		arg.ChannelDescription,
+		arg.SiteLink,
+		arg.Language,
+		arg.ImageUrl,
+		arg.Generator,
+		arg.LastBuildDate,
+	)
+	return err
+}
+
+const updateFeedSchedule = `-- name: UpdateFeedSchedule :exec
+UPDATE feed SET update_interval_seconds=$2, skip_hours=$3, skip_days=$4 WHERE id=$1
+`
+
+type UpdateFeedScheduleParams struct {
+	ID                    uuid.UUID
+	UpdateIntervalSeconds sql.NullInt32
+	SkipHours             []int32
+	SkipDays              []int32
+}
+
+func (q *Queries) UpdateFeedSchedule(ctx context.Context, arg UpdateFeedScheduleParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedSchedule,
+		arg.ID,
+		arg.UpdateIntervalSeconds,
+		pq.Array(arg.SkipHours),
+		pq.Array(arg.SkipDays),
+	)
+	return err
+}
+
+const updateFeedURL = `-- name: UpdateFeedURL :exec
+UPDATE feed SET url=$2, updated_at=now() WHERE id=$1
+`
+
+type UpdateFeedURLParams struct {
+	ID  uuid.UUID
+	Url string
+}
+
+func (q *Queries) UpdateFeedURL(ctx context.Context, arg UpdateFeedURLParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedURL, arg.ID, arg.Url)
+	return err
+}
@@ -11,14 +11,64 @@ import (
 	"github.com/google/uuid"
 )
 
+type Attachment struct {
+	ID              uuid.UUID
+	CreatedAt       time.Time
+	PostID          uuid.UUID
+	Url             string
+	MimeType        sql.NullString
+	Length          sql.NullInt64
+	DurationSeconds sql.NullInt32
+	ThumbnailUrl    sql.NullString
+}
+
+type Download struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	AttachmentID uuid.UUID
+	Path         string
+	Size         int64
+	Sha256       string
+}
+
 type Feed struct {
-	ID            uuid.UUID
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
-	Name          string
-	Url           string
-	UserID        uuid.UUID
-	LastfetchedAt sql.NullTime
+	ID                    uuid.UUID
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+	Name                  string
+	Url                   string
+	UserID                uuid.UUID
+	LastFetchedAt         sql.NullTime
+	Etag                  sql.NullString
+	LastModified          sql.NullString
+	ConsecutiveFailures   int32
+	LastError             sql.NullString
+	LastStatus            sql.NullInt32
+	NextFetchAt           sql.NullTime
+	FetchFullText         bool
+	ChannelTitle          sql.NullString
+	ChannelDescription    sql.NullString
+	SiteLink              sql.NullString
+	Language              sql.NullString
+	ImageUrl              sql.NullString
+	Generator             sql.NullString
+	LastBuildDate         sql.NullTime
+	UpdateIntervalSeconds sql.NullInt32
+	SkipHours             []int32
+	SkipDays              []int32
+	Credentials           []byte
+	DisabledAt            sql.NullTime
+	FetchCount            int32
+	NewPostCount          int32
+	ItemCount             int32
+	LastPostAt            sql.NullTime
+}
+
+type FeedUrlHistory struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	FeedID    uuid.UUID
+	Url       string
 }
 
 type Feedfollow struct {
@@ -29,15 +79,31 @@ type Feedfollow struct {
 	FeedID    uuid.UUID
 }
 
+type PostAuthor struct {
+	ID     uuid.UUID
+	PostID uuid.UUID
+	Name   string
+}
+
+type PostCategory struct {
+	ID     uuid.UUID
+	PostID uuid.UUID
+	Name   string
+}
+
 type Post struct {
-	ID          uuid.UUID
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Title       string
-	Url         string
-	Description string
-	PublishedAt sql.NullTime
-	FeedID      uuid.UUID
+	ID                   uuid.UUID
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	Title                string
+	Url                  string
+	Description          string
+	PublishedAt          sql.NullTime
+	FeedID               uuid.UUID
+	Content              sql.NullString
+	FullText             sql.NullString
+	Guid                 string
+	PublishedAtEstimated bool
 }
 
 type User struct {
@@ -46,3 +112,16 @@ type User struct {
 	UpdatedAt time.Time
 	Name      string
 }
+
+type WebsubSubscription struct {
+	ID             uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	FeedID         uuid.UUID
+	Hub            string
+	Topic          string
+	Secret         string
+	State          string
+	LeaseExpiresAt sql.NullTime
+	RequestedAt    sql.NullTime
+}
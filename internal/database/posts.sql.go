@@ -13,7 +13,7 @@ import (
 	"github.com/google/uuid"
 )
 
-const createPost = `-- name: CreatePost :exec
+const createPost = `-- name: CreatePost :execrows
 INSERT INTO posts (
     id,
     created_at,
@@ -22,25 +22,34 @@ INSERT INTO posts (
     url,
     description,
     published_at,
-    feed_id
-) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8
+    feed_id,
+    content,
+    guid,
+    published_at_estimated
 )
+SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+WHERE NOT EXISTS (
+    SELECT 1 FROM posts WHERE feed_id = $8 AND url = $5 AND guid = url
+)
+ON CONFLICT (feed_id, guid) DO NOTHING
 `
 
 type CreatePostParams struct {
-	ID          uuid.UUID
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Title       string
-	Url         string
-	Description string
-	PublishedAt sql.NullTime
-	FeedID      uuid.UUID
+	ID                   uuid.UUID
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	Title                string
+	Url                  string
+	Description          string
+	PublishedAt          sql.NullTime
+	FeedID               uuid.UUID
+	Content              sql.NullString
+	Guid                 string
+	PublishedAtEstimated bool
 }
 
-func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) error {
-	_, err := q.db.ExecContext(ctx, createPost,
+func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createPost,
 		arg.ID,
 		arg.CreatedAt,
 		arg.UpdatedAt,
@@ -49,29 +58,74 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) error {
 		arg.Description,
 		arg.PublishedAt,
 		arg.FeedID,
+		arg.Content,
+		arg.Guid,
+		arg.PublishedAtEstimated,
 	)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getPostByUrl = `-- name: GetPostByUrl :one
+SELECT id, created_at, updated_at, title, url, description, published_at, feed_id, content, full_text, guid, published_at_estimated FROM posts WHERE url = $1 LIMIT 1
+`
+
+func (q *Queries) GetPostByUrl(ctx context.Context, url string) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getPostByUrl, url)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Url,
+		&i.Description,
+		&i.PublishedAt,
+		&i.FeedID,
+		&i.Content,
+		&i.FullText,
+		&i.Guid,
+		&i.PublishedAtEstimated,
+	)
+	return i, err
 }
 
 const getPostsForUser = `-- name: GetPostsForUser :many
 
 SELECT
-    posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id
+    posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.content, posts.full_text, posts.guid, posts.published_at_estimated
 FROM posts
 JOIN feedfollows ON posts.feed_id = feedfollows.feed_id
 JOIN users ON feedfollows.user_id = users.id
 WHERE users.name = $1
+  AND ($2::text IS NULL OR EXISTS (
+    SELECT 1 FROM post_authors
+    WHERE post_authors.post_id = posts.id AND lower(post_authors.name) = lower($2)
+  ))
+  AND ($3::text IS NULL OR EXISTS (
+    SELECT 1 FROM post_categories
+    WHERE post_categories.post_id = posts.id AND lower(post_categories.name) = lower($3)
+  ))
 ORDER BY posts.published_at DESC
-LIMIT $2
+LIMIT $4
 `
 
 type GetPostsForUserParams struct {
-	Name  string
-	Limit int32
+	Name     string
+	Author   sql.NullString
+	Category sql.NullString
+	Limit    int32
 }
 
 func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams) ([]Post, error) {
-	rows, err := q.db.QueryContext(ctx, getPostsForUser, arg.Name, arg.Limit)
+	rows, err := q.db.QueryContext(ctx, getPostsForUser,
+		arg.Name,
+		arg.Author,
+		arg.Category,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -88,6 +142,10 @@ func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams
 			&i.Description,
 			&i.PublishedAt,
 			&i.FeedID,
+			&i.Content,
+			&i.FullText,
+			&i.Guid,
+			&i.PublishedAtEstimated,
 		); err != nil {
 			return nil, err
 		}
@@ -101,3 +159,17 @@ func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams
 	}
 	return items, nil
 }
+
+const updatePostFullText = `-- name: UpdatePostFullText :exec
+UPDATE posts SET full_text=$2, updated_at=now() WHERE id=$1
+`
+
+type UpdatePostFullTextParams struct {
+	ID       uuid.UUID
+	FullText sql.NullString
+}
+
+func (q *Queries) UpdatePostFullText(ctx context.Context, arg UpdatePostFullTextParams) error {
+	_, err := q.db.ExecContext(ctx, updatePostFullText, arg.ID, arg.FullText)
+	return err
+}
@@ -0,0 +1,257 @@
+package download
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Uttam1916/Gator/internal/httpclient"
+)
+
+// partial downloads live next to their destination until they complete
+const partialSuffix = ".part"
+
+// errStalled cancels a download that stopped sending data
+var errStalled = errors.New("no data received")
+
+// Result describes a finished download
+type Result struct {
+	Path   string
+	Size   int64
+	SHA256 string
+}
+
+// Fetch downloads rawURL to dest, resuming from <id>.part next to it when a
+// previous attempt was interrupted. id has to be unique per file, titles are
+// not. The size is checked against what the server announced and, when
+// known, against expectedSize.
+func Fetch(ctx context.Context, client *httpclient.Client, rawURL, dest, id string, expectedSize int64) (Result, error) {
+	partial := filepath.Join(filepath.Dir(dest), id+partialSuffix)
+	var offset int64
+	if info, err := os.Stat(partial); err == nil {
+		offset = info.Size()
+	}
+
+	// media files take far longer than a feed, so the client wide timeout
+	// only limits how long the server may go without sending anything
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	idle := client.HTTP.Timeout
+	var timer *time.Timer
+	if idle > 0 {
+		timer = time.AfterFunc(idle, func() { cancel(errStalled) })
+		defer timer.Stop()
+	}
+
+	req, err := client.NewRequest(ctx, rawURL)
+	if err != nil {
+		return Result{}, fmt.Errorf("couldnt form request: %v", err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	downloader := *client.HTTP
+	downloader.Timeout = 0
+	resp, err := downloader.Do(req)
+	if err != nil {
+		if errors.Is(context.Cause(ctx), errStalled) {
+			return Result{}, fmt.Errorf("server did not answer within %s", idle)
+		}
+		return Result{}, fmt.Errorf("error recieving response: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var total int64 = -1
+	flags := os.O_CREATE | os.O_WRONLY
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// the server ignored the range, start over
+		offset = 0
+		flags |= os.O_TRUNC
+		total = resp.ContentLength
+	case http.StatusPartialContent:
+		start, size, err := parseContentRange(resp.Header.Get("Content-Range"))
+		if err != nil {
+			return Result{}, err
+		}
+		if start != offset {
+			return Result{}, fmt.Errorf("server resumed at byte %d, expected %d", start, offset)
+		}
+		flags |= os.O_APPEND
+		total = size
+	case http.StatusRequestedRangeNotSatisfiable:
+		// the partial file already holds everything
+		if _, size, err := parseContentRange(resp.Header.Get("Content-Range")); err == nil && size == offset {
+			return finish(partial, dest, offset, expectedSize)
+		}
+		os.Remove(partial)
+		return Result{}, fmt.Errorf("stale partial download removed, try again")
+	default:
+		return Result{}, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	file, err := os.OpenFile(partial, flags, 0644)
+	if err != nil {
+		return Result{}, err
+	}
+	var body io.Reader = resp.Body
+	if timer != nil {
+		body = &idleReader{r: resp.Body, timer: timer, idle: idle}
+	}
+	written, copyErr := io.Copy(file, body)
+	closeErr := file.Close()
+	if copyErr != nil {
+		if errors.Is(context.Cause(ctx), errStalled) {
+			copyErr = fmt.Errorf("%w for %s", errStalled, idle)
+		}
+		return Result{}, fmt.Errorf("download interrupted after %d bytes: %v", offset+written, copyErr)
+	}
+	if closeErr != nil {
+		return Result{}, closeErr
+	}
+	size := offset + written
+	if total >= 0 && size != total {
+		return Result{}, fmt.Errorf("size mismatch: got %d bytes, server announced %d", size, total)
+	}
+	if total < 0 {
+		expectedSize = 0
+	}
+	return finish(partial, dest, size, expectedSize)
+}
+
+// idleReader pushes the stall timer back whenever data arrives
+type idleReader struct {
+	r     io.Reader
+	timer *time.Timer
+	idle  time.Duration
+}
+
+func (r *idleReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.timer.Reset(r.idle)
+	}
+	return n, err
+}
+
+// finish verifies and hashes the partial file and moves it into place
+func finish(partial, dest string, size, expectedSize int64) (Result, error) {
+	file, err := os.Open(partial)
+	if err != nil {
+		return Result{}, err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	hashed, err := io.Copy(hash, file)
+	if err != nil {
+		return Result{}, err
+	}
+	if hashed != size {
+		return Result{}, fmt.Errorf("size mismatch: wrote %d bytes, found %d on disk", size, hashed)
+	}
+	// enclosure lengths are often placeholders, only trust plausible ones
+	if expectedSize > 1 && expectedSize != size {
+		fmt.Printf("warning: %s is %d bytes, the feed announced %d\n", dest, size, expectedSize)
+	}
+	if err := os.Rename(partial, dest); err != nil {
+		return Result{}, err
+	}
+	return Result{
+		Path:   dest,
+		Size:   size,
+		SHA256: hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+// parseContentRange reads "bytes start-end/size" and returns start and size
+func parseContentRange(value string) (int64, int64, error) {
+	value = strings.TrimSpace(value)
+	unit, spec, ok := strings.Cut(value, " ")
+	if !ok || unit != "bytes" {
+		return 0, 0, fmt.Errorf("invalid Content-Range: %q", value)
+	}
+	rng, sizeText, ok := strings.Cut(spec, "/")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid Content-Range: %q", value)
+	}
+	size := int64(-1)
+	if sizeText != "*" {
+		parsed, err := strconv.ParseInt(sizeText, 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid Content-Range: %q", value)
+		}
+		size = parsed
+	}
+	if rng == "*" {
+		return 0, size, nil
+	}
+	startText, _, ok := strings.Cut(rng, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid Content-Range: %q", value)
+	}
+	start, err := strconv.ParseInt(startText, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid Content-Range: %q", value)
+	}
+	return start, size, nil
+}
+
+// SanitizeFilename strips characters that are unsafe in file names on common
+// filesystems and trims the result to a sensible length
+func SanitizeFilename(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r < 32, strings.ContainsRune(`<>:"/\|?*`, r):
+			b.WriteRune('_')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	clean := strings.Join(strings.Fields(b.String()), " ")
+	clean = strings.Trim(clean, ". ")
+	if len(clean) > 120 {
+		clean = strings.TrimSpace(truncateRunes(clean, 120))
+	}
+	if clean == "" {
+		clean = "untitled"
+	}
+	return clean
+}
+
+func truncateRunes(s string, limit int) string {
+	for i := range s {
+		if i > limit {
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// Extension picks a file extension from the url path, falling back to the
+// mime type
+func Extension(rawURL, mimeType string) string {
+	if parsed, err := url.Parse(rawURL); err == nil {
+		if ext := path.Ext(parsed.Path); ext != "" && len(ext) <= 6 {
+			return strings.ToLower(ext)
+		}
+	}
+	if mimeType != "" {
+		if exts, err := mime.ExtensionsByType(mimeType); err == nil && len(exts) > 0 {
+			return exts[0]
+		}
+	}
+	return ""
+}
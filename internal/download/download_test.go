@@ -0,0 +1,63 @@
+package download
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Uttam1916/Gator/internal/httpclient"
+)
+
+func newTestServer(files map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeContent(w, r, r.URL.Path, time.Time{}, strings.NewReader(content))
+	}))
+}
+
+func TestFetchResumesItsOwnPartialFile(t *testing.T) {
+	srv := newTestServer(map[string]string{
+		"/a.mp3": "episode one audio",
+		"/b.mp3": "episode two audio, longer",
+	})
+	defer srv.Close()
+	client, err := httpclient.New(httpclient.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	dest := filepath.Join(dir, "Bonus.mp3")
+
+	// an interrupted download of a, and b sharing its title
+	if err := os.WriteFile(filepath.Join(dir, "a"+partialSuffix), []byte("episode "), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := Fetch(context.Background(), client, srv.URL+"/b.mp3", dest, "b", 0)
+	if err != nil {
+		t.Fatalf("Fetch b: %v", err)
+	}
+	if got, _ := os.ReadFile(result.Path); string(got) != "episode two audio, longer" {
+		t.Errorf("b = %q", got)
+	}
+
+	dest = filepath.Join(dir, "Bonus-a.mp3")
+	result, err = Fetch(context.Background(), client, srv.URL+"/a.mp3", dest, "a", 0)
+	if err != nil {
+		t.Fatalf("Fetch a: %v", err)
+	}
+	if got, _ := os.ReadFile(result.Path); string(got) != "episode one audio" {
+		t.Errorf("resumed a = %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a"+partialSuffix)); !os.IsNotExist(err) {
+		t.Errorf("partial file left behind: %v", err)
+	}
+}
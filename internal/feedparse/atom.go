@@ -0,0 +1,173 @@
+package feedparse
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+const atomNamespace = "http://www.w3.org/2005/Atom"
+
+type atomDocument struct {
+	syndicationFields
+	Lang      string       `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	Authors   []atomPerson `xml:"author"`
+	Title     string       `xml:"title"`
+	Subtitle  string       `xml:"subtitle"`
+	Links     []atomLink   `xml:"link"`
+	Icon      string       `xml:"icon"`
+	Logo      string       `xml:"logo"`
+	Generator string       `xml:"generator"`
+	Updated   string       `xml:"updated"`
+	Entries   []atomEntry  `xml:"entry"`
+}
+
+type atomEntry struct {
+	mediaFields
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Links      []atomLink     `xml:"link"`
+	Summary    atomText       `xml:"summary"`
+	Content    atomText       `xml:"content"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Authors    []atomPerson   `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomPerson struct {
+	Name  string `xml:"name"`
+	Email string `xml:"email"`
+}
+
+type atomCategory struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr"`
+}
+
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+// atom text constructs can be plain text, escaped html or inline xhtml
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Text  string `xml:",chardata"`
+	Inner string `xml:",innerxml"`
+}
+
+func (t atomText) value() string {
+	if t.Type == "xhtml" {
+		return strings.TrimSpace(t.Inner)
+	}
+	return strings.TrimSpace(t.Text)
+}
+
+// AtomParser handles atom 1.0 documents
+type AtomParser struct{}
+
+func (AtomParser) Name() string {
+	return "atom"
+}
+
+func (AtomParser) Detect(h Hint) bool {
+	if h.Root.Local == "feed" {
+		return h.Root.Space == "" || h.Root.Space == atomNamespace
+	}
+	return h.Root.Local == "" && strings.Contains(h.ContentType, "atom+xml")
+}
+
+func (AtomParser) Parse(body []byte) (*Feed, error) {
+	var doc atomDocument
+	err := xml.Unmarshal(body, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("couldnt convert atom xml into go struct: %w", err)
+	}
+	feed := &Feed{
+		Title:         doc.Title,
+		Link:          alternateLink(doc.Links),
+		Description:   doc.Subtitle,
+		Language:      doc.Lang,
+		Image:         strings.TrimSpace(doc.Logo),
+		Generator:     strings.TrimSpace(doc.Generator),
+		LastBuildDate: strings.TrimSpace(doc.Updated),
+		Schedule:      Schedule{Interval: doc.interval()},
+	}
+	for _, link := range doc.Links {
+		switch {
+		case link.Rel == "hub" && feed.Hub == "":
+			feed.Hub = strings.TrimSpace(link.Href)
+		case link.Rel == "self" && feed.Self == "":
+			feed.Self = strings.TrimSpace(link.Href)
+		}
+	}
+	if feed.Image == "" {
+		feed.Image = strings.TrimSpace(doc.Icon)
+	}
+	for _, entry := range doc.Entries {
+		item := Item{
+			Title:       entry.Title,
+			Link:        alternateLink(entry.Links),
+			Description: entry.Summary.value(),
+			Content:     entry.Content.value(),
+			PubDate:     entry.Published,
+			Updated:     strings.TrimSpace(entry.Updated),
+			GUID:        entry.ID,
+		}
+		// fall back to the full content when there is no summary
+		if item.Description == "" {
+			item.Description = item.Content
+		}
+		if item.PubDate == "" {
+			item.PubDate = entry.Updated
+		}
+		// entries inherit the feed's authors when they name none
+		authors := entry.Authors
+		if len(authors) == 0 {
+			authors = doc.Authors
+		}
+		var names []string
+		for _, author := range authors {
+			names = append(names, author.Name)
+		}
+		item.Authors = cleanNames(names)
+		var categories []string
+		for _, category := range entry.Categories {
+			if category.Label != "" {
+				categories = append(categories, category.Label)
+			} else {
+				categories = append(categories, category.Term)
+			}
+		}
+		item.Categories = cleanNames(categories)
+		for _, link := range entry.Links {
+			if link.Rel == "enclosure" {
+				item.Enclosures = append(item.Enclosures, Enclosure{
+					URL:    link.Href,
+					Type:   link.Type,
+					Length: parseLength(link.Length),
+				})
+			}
+		}
+		item.Enclosures = entry.attachments(item.Enclosures)
+		feed.Items = append(feed.Items, item)
+	}
+	unescape(feed)
+	return feed, nil
+}
+
+// alternateLink picks the alternate link, which atom treats as the default rel
+func alternateLink(links []atomLink) string {
+	for _, link := range links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return link.Href
+		}
+	}
+	if len(links) > 0 {
+		return links[0].Href
+	}
+	return ""
+}
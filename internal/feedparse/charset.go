@@ -0,0 +1,84 @@
+package feedparse
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html/charset"
+)
+
+// matches the encoding pseudo attribute of an xml declaration
+var prologEncoding = regexp.MustCompile(`^(<\?xml[^>]*?encoding\s*=\s*["'])([A-Za-z0-9._:-]+)(["'])`)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// toUTF8 transcodes body to utf-8, taking the charset from the Content-Type
+// header first and the xml prolog second. Servers often claim utf-8 for
+// every file, so a body that is not valid utf-8 goes by its prolog instead.
+// The prolog is rewritten so encoding/xml does not reject the converted
+// document.
+func toUTF8(contentType string, body []byte) ([]byte, error) {
+	body = bytes.TrimPrefix(body, utf8BOM)
+	label := headerCharset(contentType)
+	if label == "" || (isUTF8Label(label) && !utf8.Valid(body)) {
+		if declared := declaredCharset(body); declared != "" {
+			label = declared
+		}
+	}
+	if label == "" || isUTF8Label(label) {
+		return rewriteProlog(body), nil
+	}
+	enc, name := charset.Lookup(label)
+	if enc == nil {
+		return nil, fmt.Errorf("unsupported charset %q", label)
+	}
+	if name == "utf-8" {
+		return rewriteProlog(body), nil
+	}
+	converted, err := enc.NewDecoder().Bytes(body)
+	if err != nil {
+		return nil, fmt.Errorf("couldnt decode %s body: %w", name, err)
+	}
+	return rewriteProlog(converted), nil
+}
+
+func headerCharset(contentType string) string {
+	if contentType == "" {
+		return ""
+	}
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(params["charset"])
+}
+
+func declaredCharset(body []byte) string {
+	match := prologEncoding.FindSubmatch(bytes.TrimSpace(body[:min(len(body), 1024)]))
+	if match == nil {
+		return ""
+	}
+	return string(match[2])
+}
+
+func isUTF8Label(label string) bool {
+	label = strings.ToLower(label)
+	return label == "utf-8" || label == "utf8"
+}
+
+func rewriteProlog(body []byte) []byte {
+	trimmed := bytes.TrimLeft(body, " \t\r\n")
+	loc := prologEncoding.FindSubmatchIndex(trimmed)
+	if loc == nil || isUTF8Label(string(trimmed[loc[4]:loc[5]])) {
+		return body
+	}
+	var out bytes.Buffer
+	out.Write(trimmed[:loc[4]])
+	out.WriteString("UTF-8")
+	out.Write(trimmed[loc[5]:])
+	return out.Bytes()
+}
@@ -0,0 +1,26 @@
+package feedparse
+
+import "testing"
+
+func TestParseCharset(t *testing.T) {
+	latin1 := "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?><rss version=\"2.0\"><channel><title>Caf\xe9</title></channel></rss>"
+	tests := []struct {
+		name        string
+		contentType string
+	}{
+		{"prolog only", "application/rss+xml"},
+		{"header charset", "application/rss+xml; charset=iso-8859-1"},
+		{"wrong utf-8 header", "text/xml; charset=utf-8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed, err := Parse(tt.contentType, []byte(latin1))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if feed.Title != "Café" {
+				t.Errorf("title = %q, want %q", feed.Title, "Café")
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package feedparse
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Candidate is a feed advertised by an html page
+type Candidate struct {
+	URL   string
+	Title string
+	Type  string
+}
+
+// feed types browsers and readers look for in <link rel="alternate">
+var discoverableTypes = map[string]bool{
+	"application/rss+xml":   true,
+	"application/atom+xml":  true,
+	"application/rdf+xml":   true,
+	"application/feed+json": true,
+}
+
+// IsHTML reports whether a response is a web page rather than a feed
+func IsHTML(contentType string, body []byte) bool {
+	if strings.Contains(contentType, "text/html") || strings.Contains(contentType, "application/xhtml+xml") {
+		return true
+	}
+	root := rootElement(body)
+	if strings.EqualFold(root.Local, "html") {
+		return true
+	}
+	// html5 pages are rarely well formed xml, fall back to a prefix check
+	head := strings.ToLower(string(bytes.TrimSpace(body[:min(len(body), 512)])))
+	return strings.HasPrefix(head, "<!doctype html") || strings.HasPrefix(head, "<html")
+}
+
+// Discover lists the feeds linked from an html page, resolving relative
+// hrefs against pageURL
+func Discover(pageURL string, body []byte) ([]Candidate, error) {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	var candidates []Candidate
+	seen := make(map[string]bool)
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && (n.Data == "link" || n.Data == "a") {
+			if candidate, ok := linkCandidate(base, n); ok && !seen[candidate.URL] {
+				seen[candidate.URL] = true
+				candidates = append(candidates, candidate)
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+	return candidates, nil
+}
+
+func linkCandidate(base *url.URL, n *html.Node) (Candidate, bool) {
+	var rel, href, title, mediaType string
+	for _, attr := range n.Attr {
+		switch strings.ToLower(attr.Key) {
+		case "rel":
+			rel = strings.ToLower(attr.Val)
+		case "href":
+			href = strings.TrimSpace(attr.Val)
+		case "title":
+			title = strings.TrimSpace(attr.Val)
+		case "type":
+			mediaType = strings.ToLower(strings.TrimSpace(attr.Val))
+		}
+	}
+	if href == "" || !discoverableTypes[mediaType] {
+		return Candidate{}, false
+	}
+	if !hasToken(rel, "alternate") {
+		return Candidate{}, false
+	}
+	ref, err := url.Parse(href)
+	if err != nil {
+		return Candidate{}, false
+	}
+	// a remote page must not be able to point us at local files or commands
+	resolved := base.ResolveReference(ref)
+	if resolved.Scheme != "http" && resolved.Scheme != "https" {
+		return Candidate{}, false
+	}
+	return Candidate{
+		URL:   resolved.String(),
+		Title: title,
+		Type:  mediaType,
+	}, true
+}
+
+// hasToken checks a space separated attribute like rel for a single value
+func hasToken(list, token string) bool {
+	for _, field := range strings.Fields(list) {
+		if field == token {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package feedparse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiscover(t *testing.T) {
+	page := `<!doctype html>
+<html><head>
+<link rel="alternate" type="application/rss+xml" title="Posts" href="/feed.xml">
+<link rel="alternate" type="application/atom+xml" href="https://example.com/atom.xml">
+<link rel="alternate" type="application/rss+xml" href="/feed.xml">
+<link rel="alternate" type="application/json" href="/wp-json/wp/v2/posts/1">
+<link rel="alternate" type="application/rss+xml" href="file:///etc/passwd">
+<link rel="alternate" type="application/rss+xml" href="exec:backup">
+<link rel="stylesheet" type="text/css" href="/style.css">
+</head><body></body></html>`
+	got, err := Discover("https://example.com/blog/", []byte(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Candidate{
+		{URL: "https://example.com/feed.xml", Title: "Posts", Type: "application/rss+xml"},
+		{URL: "https://example.com/atom.xml", Type: "application/atom+xml"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Discover = %+v\nwant       %+v", got, want)
+	}
+}
+
+func TestIsHTML(t *testing.T) {
+	tests := []struct {
+		contentType string
+		body        string
+		want        bool
+	}{
+		{"text/html; charset=utf-8", `<rss/>`, true},
+		{"", `<!DOCTYPE html><p>hi`, true},
+		{"application/xml", `<html xmlns="http://www.w3.org/1999/xhtml"></html>`, true},
+		{"application/rss+xml", `<rss version="2.0"></rss>`, false},
+		{"", `{"version": "https://jsonfeed.org/version/1"}`, false},
+	}
+	for _, tt := range tests {
+		if got := IsHTML(tt.contentType, []byte(tt.body)); got != tt.want {
+			t.Errorf("IsHTML(%q, %q) = %v, want %v", tt.contentType, tt.body, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package feedparse
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"html"
+	"strings"
+	"time"
+)
+
+// ErrUnknownFormat is returned when no registered parser accepts a document
+var ErrUnknownFormat = errors.New("unrecognized feed format")
+
+// Feed is the normalized form every parser produces
+type Feed struct {
+	Title       string
+	Link        string
+	Description string
+	Language    string
+	// Image is the logo or icon the feed advertises for itself
+	Image         string
+	Generator     string
+	LastBuildDate string
+	Schedule      Schedule
+	// Hub is the websub hub announced with rel="hub", Self is the feed's
+	// canonical url from rel="self" which hubs use as the topic
+	Hub   string
+	Self  string
+	Items []Item
+}
+
+// Item is a single entry of a feed, ready to be stored as a post
+type Item struct {
+	Title       string
+	Link        string
+	Description string
+	// Content is the full article when the feed provides one
+	Content string
+	PubDate string
+	// Updated is when the item last changed, only atom has it
+	Updated    string
+	GUID       string
+	Authors    []string
+	Categories []string
+	Enclosures []Enclosure
+}
+
+// Enclosure is a media file attached to an item
+type Enclosure struct {
+	URL       string
+	Type      string
+	Length    int64
+	Duration  time.Duration
+	Thumbnail string
+}
+
+// Hint carries what is known about a document before it is parsed
+type Hint struct {
+	ContentType string
+	// Root is the first xml element of the body, empty for non xml documents
+	Root xml.Name
+	Body []byte
+}
+
+// Parser turns one feed format into the normalized model
+type Parser interface {
+	Name() string
+	Detect(h Hint) bool
+	Parse(body []byte) (*Feed, error)
+}
+
+// Registry holds parsers in the order they are tried
+type Registry struct {
+	parsers []Parser
+}
+
+func NewRegistry(parsers ...Parser) *Registry {
+	return &Registry{parsers: parsers}
+}
+
+// Register adds a parser, it takes priority over the ones already registered
+func (r *Registry) Register(p Parser) {
+	r.parsers = append([]Parser{p}, r.parsers...)
+}
+
+// Detect returns the first parser that accepts the document
+func (r *Registry) Detect(contentType string, body []byte) (Parser, error) {
+	hint := Hint{
+		ContentType: contentType,
+		Root:        rootElement(body),
+		Body:        body,
+	}
+	for _, p := range r.parsers {
+		if p.Detect(hint) {
+			return p, nil
+		}
+	}
+	return nil, ErrUnknownFormat
+}
+
+// Parse converts body to utf-8, sniffs its format and parses it with the
+// matching parser
+func (r *Registry) Parse(contentType string, body []byte) (*Feed, error) {
+	body, err := toUTF8(contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	p, err := r.Detect(contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	return p.Parse(body)
+}
+
+// Default knows every format shipped with gator
+var Default = NewRegistry(
+	JSONParser{},
+	AtomParser{},
+	RDFParser{},
+	RSSParser{},
+)
+
+func Register(p Parser) {
+	Default.Register(p)
+}
+
+func Parse(contentType string, body []byte) (*Feed, error) {
+	return Default.Parse(contentType, body)
+}
+
+// rootElement returns the name of the first element in an xml document
+func rootElement(body []byte) xml.Name {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			return xml.Name{}
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			return start.Name
+		}
+	}
+}
+
+// cleanNames trims and dedupes author or category names. rss authors are
+// email addresses, "jane@example.com (Jane Doe)" becomes "Jane Doe".
+func cleanNames(names []string) []string {
+	var cleaned []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		name = strings.TrimSpace(html.UnescapeString(name))
+		if open := strings.Index(name, "("); open > 0 && strings.Contains(name[:open], "@") && strings.HasSuffix(name, ")") {
+			name = strings.TrimSpace(name[open+1 : len(name)-1])
+		}
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, name)
+	}
+	return cleaned
+}
+
+// unescape cleans up entities left behind in xml text fields
+func unescape(feed *Feed) {
+	feed.Title = html.UnescapeString(feed.Title)
+	feed.Description = html.UnescapeString(feed.Description)
+	for i := range feed.Items {
+		feed.Items[i].Title = html.UnescapeString(feed.Items[i].Title)
+		feed.Items[i].Description = html.UnescapeString(feed.Items[i].Description)
+	}
+}
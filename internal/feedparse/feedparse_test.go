@@ -0,0 +1,183 @@
+package feedparse
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func TestParseFixtures(t *testing.T) {
+	tests := []struct {
+		fixture     string
+		contentType string
+		format      string
+		want        Feed
+		first       Item
+	}{
+		{
+			fixture:     "rss.xml",
+			contentType: "application/rss+xml",
+			format:      "rss",
+			want: Feed{
+				Title:       "Example Podcast",
+				Link:        "https://example.com/",
+				Description: "Episodes & notes",
+				Language:    "en-us",
+				Image:       "https://example.com/cover.png",
+				Schedule:    Schedule{Interval: time.Hour},
+				Hub:         "https://hub.example.com/",
+				Self:        "https://example.com/feed.xml",
+			},
+			first: Item{
+				Title:       "Episode 2",
+				Link:        "https://example.com/episodes/2?utm_source=rss",
+				Description: "The second one",
+				PubDate:     "Tue, 02 Jan 2024 10:00:00 GMT",
+				GUID:        "episode-2",
+				Authors:     []string{"Jane Host"},
+				Categories:  []string{"Tech"},
+				Enclosures:  []Enclosure{{URL: "https://cdn.example.com/2.mp3", Type: "audio/mpeg", Length: 1234}},
+			},
+		},
+		{
+			fixture:     "rdf.xml",
+			contentType: "application/rdf+xml",
+			format:      "rdf",
+			want: Feed{
+				Title:       "Example RDF",
+				Link:        "https://example.org/",
+				Description: "An rss 1.0 feed",
+				Schedule:    Schedule{Interval: 30 * time.Minute},
+			},
+			first: Item{
+				Title:       "First post",
+				Link:        "https://example.org/posts/1",
+				Description: "Hello",
+				PubDate:     "2024-01-01T10:00:00Z",
+				GUID:        "https://example.org/posts/1",
+				Authors:     []string{"Sam Writer"},
+			},
+		},
+		{
+			fixture:     "atom.xml",
+			contentType: "application/atom+xml",
+			format:      "atom",
+			want: Feed{
+				Title:         "Example Atom",
+				Link:          "https://example.net/",
+				LastBuildDate: "2024-01-02T10:00:00Z",
+				Self:          "https://example.net/atom.xml",
+			},
+			first: Item{
+				Title:       "Second entry",
+				Link:        "https://example.net/2",
+				Description: "Summary two",
+				Content:     "<p>Full text</p>",
+				PubDate:     "2024-01-02T10:00:00Z",
+				Updated:     "2024-01-02T10:00:00Z",
+				GUID:        "tag:example.net,2024:2",
+				Authors:     []string{"Alex Author"},
+				Categories:  []string{"news"},
+			},
+		},
+		{
+			fixture:     "feed.json",
+			contentType: "application/feed+json",
+			format:      "json",
+			want: Feed{
+				Title: "Example JSON",
+				Link:  "https://example.io/",
+				Self:  "https://example.io/feed.json",
+			},
+			first: Item{
+				Title:       "Second item",
+				Link:        "https://example.io/2",
+				Description: "<p>Two</p>",
+				Content:     "<p>Two</p>",
+				PubDate:     "2024-01-02T10:00:00Z",
+				GUID:        "2",
+				Authors:     []string{"Kim Coder"},
+				Categories:  []string{"go"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			body := readFixture(t, tt.fixture)
+			p, err := Default.Detect(tt.contentType, body)
+			if err != nil {
+				t.Fatalf("Detect: %v", err)
+			}
+			if p.Name() != tt.format {
+				t.Errorf("Detect picked %s, want %s", p.Name(), tt.format)
+			}
+			feed, err := Parse(tt.contentType, body)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if len(feed.Items) == 0 {
+				t.Fatal("no items parsed")
+			}
+			first := feed.Items[0]
+			feed.Items = nil
+			if !reflect.DeepEqual(*feed, tt.want) {
+				t.Errorf("feed = %+v\nwant  %+v", *feed, tt.want)
+			}
+			if !reflect.DeepEqual(first, tt.first) {
+				t.Errorf("first item = %+v\nwant        %+v", first, tt.first)
+			}
+		})
+	}
+}
+
+func TestDetectSniffsBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		format      string
+	}{
+		{"rss as text/xml", "text/xml", `<rss version="2.0"><channel/></rss>`, "rss"},
+		{"rss without content type", "", `<?xml version="1.0"?><rss><channel/></rss>`, "rss"},
+		{"atom as application/xml", "application/xml", `<feed xmlns="http://www.w3.org/2005/Atom"/>`, "atom"},
+		{"atom without namespace", "", `<feed><title>x</title></feed>`, "atom"},
+		{"rdf as text/plain", "text/plain", `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"/>`, "rdf"},
+		{"json feed as application/json", "application/json", `{"version": "https://jsonfeed.org/version/1", "items": []}`, "json"},
+		{"broken rss trusted by content type", "application/rss+xml", `<<rss`, "rss"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Default.Detect(tt.contentType, []byte(tt.body))
+			if err != nil {
+				t.Fatalf("Detect: %v", err)
+			}
+			if p.Name() != tt.format {
+				t.Errorf("Detect picked %s, want %s", p.Name(), tt.format)
+			}
+		})
+	}
+}
+
+func TestDetectRejectsUnknownFormats(t *testing.T) {
+	for _, body := range []string{
+		`<html><body>hi</body></html>`,
+		`{"title": "not a feed"}`,
+		`plain text`,
+	} {
+		if _, err := Default.Detect("", []byte(body)); !errors.Is(err, ErrUnknownFormat) {
+			t.Errorf("Detect(%q) = %v, want ErrUnknownFormat", body, err)
+		}
+	}
+}
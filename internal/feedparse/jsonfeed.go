@@ -0,0 +1,169 @@
+package feedparse
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type jsonDocument struct {
+	Version     string     `json:"version"`
+	Title       string     `json:"title"`
+	HomePageURL string     `json:"home_page_url"`
+	FeedURL     string     `json:"feed_url"`
+	Hubs        []jsonHub  `json:"hubs"`
+	Description string     `json:"description"`
+	Icon        string     `json:"icon"`
+	Favicon     string     `json:"favicon"`
+	Language    string     `json:"language"`
+	Items       []jsonItem `json:"items"`
+}
+
+type jsonItem struct {
+	ID            jsonID           `json:"id"`
+	URL           string           `json:"url"`
+	Title         string           `json:"title"`
+	ContentHTML   string           `json:"content_html"`
+	ContentText   string           `json:"content_text"`
+	Summary       string           `json:"summary"`
+	DatePublished string           `json:"date_published"`
+	DateModified  string           `json:"date_modified"`
+	Image         string           `json:"image"`
+	Tags          []string         `json:"tags"`
+	Authors       []jsonAuthor     `json:"authors"`
+	Author        *jsonAuthor      `json:"author"`
+	Attachments   []jsonAttachment `json:"attachments"`
+}
+
+// jsonID is an item id, the spec asks readers to turn numeric ids into
+// strings rather than reject them
+type jsonID string
+
+func (id *jsonID) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*id = jsonID(text)
+		return nil
+	}
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return fmt.Errorf("id must be a string or a number, got %s", data)
+	}
+	*id = jsonID(number.String())
+	return nil
+}
+
+type jsonHub struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type jsonAttachment struct {
+	URL               string  `json:"url"`
+	MimeType          string  `json:"mime_type"`
+	SizeInBytes       int64   `json:"size_in_bytes"`
+	DurationInSeconds float64 `json:"duration_in_seconds"`
+}
+
+// JSONParser handles json feed 1.0 and 1.1 documents
+type JSONParser struct{}
+
+func (JSONParser) Name() string {
+	return "json"
+}
+
+// Detect accepts the json feed content type or any json object with a
+// top level "version" key
+func (JSONParser) Detect(h Hint) bool {
+	if strings.Contains(h.ContentType, "application/feed+json") {
+		return true
+	}
+	trimmed := bytes.TrimSpace(h.Body)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return false
+	}
+	var probe struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(trimmed, &probe); err != nil {
+		return false
+	}
+	return probe.Version != ""
+}
+
+func (JSONParser) Parse(body []byte) (*Feed, error) {
+	var doc jsonDocument
+	err := json.Unmarshal(body, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("couldnt convert json feed into go struct: %w", err)
+	}
+	feed := &Feed{
+		Title:       doc.Title,
+		Link:        doc.HomePageURL,
+		Description: doc.Description,
+		Language:    doc.Language,
+		Image:       doc.Icon,
+		Self:        doc.FeedURL,
+	}
+	for _, hub := range doc.Hubs {
+		if strings.EqualFold(hub.Type, "websub") && feed.Hub == "" {
+			feed.Hub = hub.URL
+		}
+	}
+	if feed.Image == "" {
+		feed.Image = doc.Favicon
+	}
+	for _, entry := range doc.Items {
+		item := Item{
+			Title:       entry.Title,
+			Link:        entry.URL,
+			Description: entry.Summary,
+			Content:     entry.ContentHTML,
+			PubDate:     entry.DatePublished,
+			GUID:        string(entry.ID),
+		}
+		if item.Content == "" {
+			item.Content = entry.ContentText
+		}
+		// summary is optional, show the full content instead
+		if item.Description == "" {
+			item.Description = item.Content
+		}
+		if item.PubDate == "" {
+			item.PubDate = entry.DateModified
+		}
+		// version 1.1 uses authors, 1.0 used a single author
+		authors := entry.Authors
+		if len(authors) == 0 && entry.Author != nil {
+			authors = append(authors, *entry.Author)
+		}
+		var names []string
+		for _, author := range authors {
+			names = append(names, author.Name)
+		}
+		item.Authors = cleanNames(names)
+		item.Categories = cleanNames(entry.Tags)
+		for _, attachment := range entry.Attachments {
+			item.Enclosures = append(item.Enclosures, Enclosure{
+				URL:       attachment.URL,
+				Type:      attachment.MimeType,
+				Length:    attachment.SizeInBytes,
+				Duration:  time.Duration(attachment.DurationInSeconds * float64(time.Second)),
+				Thumbnail: entry.Image,
+			})
+		}
+		// titles are optional in json feed
+		if item.Title == "" {
+			item.Title = item.Link
+		}
+		feed.Items = append(feed.Items, item)
+	}
+	return feed, nil
+}
@@ -0,0 +1,36 @@
+package feedparse
+
+import "testing"
+
+func TestJSONItemIDs(t *testing.T) {
+	body := []byte(`{
+  "version": "https://jsonfeed.org/version/1.1",
+  "title": "Ids",
+  "items": [
+    {"id": "abc", "title": "string"},
+    {"id": 42, "title": "integer"},
+    {"id": 1.5e3, "title": "exponent"},
+    {"id": null, "url": "https://example.com/4", "title": "null"}
+  ]
+}`)
+	feed, err := Parse("application/feed+json", body)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := []string{"abc", "42", "1.5e3", ""}
+	if len(feed.Items) != len(want) {
+		t.Fatalf("got %d items, want %d", len(feed.Items), len(want))
+	}
+	for i, item := range feed.Items {
+		if item.GUID != want[i] {
+			t.Errorf("%s: GUID = %q, want %q", item.Title, item.GUID, want[i])
+		}
+	}
+}
+
+func TestJSONItemIDRejectsObjects(t *testing.T) {
+	body := []byte(`{"version": "https://jsonfeed.org/version/1.1", "items": [{"id": {"a": 1}}]}`)
+	if _, err := Parse("application/feed+json", body); err == nil {
+		t.Error("Parse accepted an object as item id")
+	}
+}
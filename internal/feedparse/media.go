@@ -0,0 +1,138 @@
+package feedparse
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+type mediaContent struct {
+	URL        string           `xml:"url,attr"`
+	Type       string           `xml:"type,attr"`
+	FileSize   string           `xml:"fileSize,attr"`
+	Duration   string           `xml:"duration,attr"`
+	Thumbnails []mediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+}
+
+type mediaThumbnail struct {
+	URL string `xml:"url,attr"`
+}
+
+type mediaGroup struct {
+	Contents   []mediaContent   `xml:"http://search.yahoo.com/mrss/ content"`
+	Thumbnails []mediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+}
+
+type itunesImage struct {
+	Href string `xml:"href,attr"`
+}
+
+// mediaFields collects media rss and itunes extensions, it is embedded
+// first in item structs so the namespaced elements win over plain ones
+type mediaFields struct {
+	MediaContents   []mediaContent   `xml:"http://search.yahoo.com/mrss/ content"`
+	MediaThumbnails []mediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	MediaGroups     []mediaGroup     `xml:"http://search.yahoo.com/mrss/ group"`
+	ItunesDuration  string           `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+	ItunesImage     itunesImage      `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+}
+
+// attachments merges plain enclosures with the media extensions of an item
+func (m mediaFields) attachments(enclosures []Enclosure) []Enclosure {
+	contents := m.MediaContents
+	thumbnails := m.MediaThumbnails
+	for _, group := range m.MediaGroups {
+		contents = append(contents, group.Contents...)
+		thumbnails = append(thumbnails, group.Thumbnails...)
+	}
+
+	seen := make(map[string]int)
+	for i, enclosure := range enclosures {
+		seen[enclosure.URL] = i
+	}
+	for _, content := range contents {
+		if content.URL == "" {
+			continue
+		}
+		attachment := Enclosure{
+			URL:      content.URL,
+			Type:     content.Type,
+			Length:   parseLength(content.FileSize),
+			Duration: parseDuration(content.Duration),
+		}
+		if len(content.Thumbnails) > 0 {
+			attachment.Thumbnail = content.Thumbnails[0].URL
+		}
+		// media:content often repeats the enclosure with extra details
+		if i, ok := seen[content.URL]; ok {
+			enclosures[i] = mergeEnclosure(enclosures[i], attachment)
+			continue
+		}
+		seen[content.URL] = len(enclosures)
+		enclosures = append(enclosures, attachment)
+	}
+
+	thumbnail := m.ItunesImage.Href
+	if len(thumbnails) > 0 {
+		thumbnail = thumbnails[0].URL
+	}
+	duration := parseDuration(m.ItunesDuration)
+	for i := range enclosures {
+		if enclosures[i].Thumbnail == "" {
+			enclosures[i].Thumbnail = thumbnail
+		}
+		if enclosures[i].Duration == 0 {
+			enclosures[i].Duration = duration
+		}
+	}
+	return enclosures
+}
+
+func mergeEnclosure(base, extra Enclosure) Enclosure {
+	if base.Type == "" {
+		base.Type = extra.Type
+	}
+	if base.Length == 0 {
+		base.Length = extra.Length
+	}
+	if base.Duration == 0 {
+		base.Duration = extra.Duration
+	}
+	if base.Thumbnail == "" {
+		base.Thumbnail = extra.Thumbnail
+	}
+	return base
+}
+
+func parseLength(raw string) int64 {
+	length, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+	if err != nil || length < 0 {
+		return 0
+	}
+	return length
+}
+
+// parseDuration reads plain or fractional seconds as well as the
+// HH:MM:SS and MM:SS forms used by itunes:duration
+func parseDuration(raw string) time.Duration {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0
+	}
+	if !strings.Contains(raw, ":") {
+		seconds, err := strconv.ParseFloat(raw, 64)
+		if err != nil || seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds * float64(time.Second))
+	}
+	var total int64
+	for _, part := range strings.Split(raw, ":") {
+		value, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || value < 0 {
+			return 0
+		}
+		total = total*60 + value
+	}
+	return time.Duration(total) * time.Second
+}
@@ -0,0 +1,72 @@
+package feedparse
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strings"
+)
+
+// query parameters added by mailers and analytics that do not change the
+// page being linked to
+var trackingParams = map[string]bool{
+	"fbclid":  true,
+	"gclid":   true,
+	"dclid":   true,
+	"msclkid": true,
+	"mc_cid":  true,
+	"mc_eid":  true,
+	"igshid":  true,
+	"ref_src": true,
+	"_hsenc":  true,
+	"_hsmi":   true,
+}
+
+// NormalizeLink canonicalizes a link so the same article reached through
+// different tracking urls compares equal
+func NormalizeLink(raw string) string {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	if (u.Scheme == "http" && u.Port() == "80") || (u.Scheme == "https" && u.Port() == "443") {
+		u.Host = u.Hostname()
+	}
+	u.Fragment = ""
+	u.RawFragment = ""
+	if u.Path == "" {
+		u.Path = "/"
+	}
+
+	query := u.Query()
+	for key := range query {
+		if strings.HasPrefix(strings.ToLower(key), "utm_") || trackingParams[strings.ToLower(key)] {
+			query.Del(key)
+		}
+	}
+	// Encode sorts by key, which also makes parameter order irrelevant
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// Key identifies an item within its feed: the guid or atom id when the feed
+// provides one, the normalized link otherwise. Items with neither fall back
+// to their title and date, then to a hash of their text, so the key is never
+// empty.
+func (i Item) Key() string {
+	if guid := strings.TrimSpace(i.GUID); guid != "" {
+		return guid
+	}
+	if i.Link != "" {
+		return NormalizeLink(i.Link)
+	}
+	if key := strings.TrimSpace(i.Title + " " + i.PubDate); key != "" {
+		return key
+	}
+	// rss allows items that are only a description
+	sum := sha256.Sum256([]byte(i.Description + "\x00" + i.Content))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,69 @@
+package feedparse
+
+import "testing"
+
+func TestNormalizeLink(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"https://example.com/a", "https://example.com/a"},
+		{"  https://example.com/a  ", "https://example.com/a"},
+		{"HTTPS://Example.COM/a", "https://example.com/a"},
+		{"https://example.com", "https://example.com/"},
+		{"https://example.com:443/a", "https://example.com/a"},
+		{"http://example.com:80/a", "http://example.com/a"},
+		{"http://example.com:8080/a", "http://example.com:8080/a"},
+		{"https://example.com/a#comments", "https://example.com/a"},
+		{"https://example.com/a?utm_source=rss&utm_medium=feed", "https://example.com/a"},
+		{"https://example.com/a?fbclid=x&id=3", "https://example.com/a?id=3"},
+		{"https://example.com/a?b=2&a=1", "https://example.com/a?a=1&b=2"},
+		{"/relative/path", "/relative/path"},
+		{"not a url", "not a url"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeLink(tt.in); got != tt.want {
+			t.Errorf("NormalizeLink(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestItemKey(t *testing.T) {
+	tests := []struct {
+		name string
+		item Item
+		want string
+	}{
+		{"guid wins", Item{GUID: " abc ", Link: "https://example.com/a"}, "abc"},
+		{"normalized link", Item{Link: "https://Example.com/a?utm_campaign=x#top"}, "https://example.com/a"},
+		{"title and date", Item{Title: "Hello", PubDate: "Mon, 01 Jan 2024 10:00:00 GMT"}, "Hello Mon, 01 Jan 2024 10:00:00 GMT"},
+		{"description only", Item{Description: "Just a note"}, "sha256:c3b37fb97685b2eabba84e1a8abd5ce949bb05e7222ff9036b697be9e51224e4"},
+	}
+	for _, tt := range tests {
+		if got := tt.item.Key(); got != tt.want {
+			t.Errorf("%s: Key() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestItemKeyIgnoresTracking(t *testing.T) {
+	a := Item{Link: "https://example.com/post?utm_source=newsletter"}
+	b := Item{Link: "https://example.com/post?fbclid=123"}
+	if a.Key() != b.Key() {
+		t.Errorf("keys differ: %q and %q", a.Key(), b.Key())
+	}
+}
+
+func TestItemKeyDescriptionOnly(t *testing.T) {
+	a := Item{Description: "First note"}
+	b := Item{Description: "Second note"}
+	if a.Key() == "" || b.Key() == "" {
+		t.Fatalf("empty key: %q, %q", a.Key(), b.Key())
+	}
+	if a.Key() == b.Key() {
+		t.Errorf("different items share the key %q", a.Key())
+	}
+	if a.Key() != (Item{Description: "First note"}).Key() {
+		t.Error("key is not stable")
+	}
+}
@@ -0,0 +1,86 @@
+package feedparse
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+const (
+	rdfNamespace = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	dcNamespace  = "http://purl.org/dc/elements/1.1/"
+)
+
+// rss 1.0 keeps items next to the channel instead of inside it
+type rdfDocument struct {
+	Channel struct {
+		syndicationFields
+		Title       string `xml:"title"`
+		Link        string `xml:"link"`
+		Description string `xml:"description"`
+		Language    string `xml:"http://purl.org/dc/elements/1.1/ language"`
+		Date        string `xml:"http://purl.org/dc/elements/1.1/ date"`
+	} `xml:"channel"`
+	Image struct {
+		URL string `xml:"url"`
+	} `xml:"image"`
+	Items []rdfItem `xml:"item"`
+}
+
+type rdfItem struct {
+	About       string   `xml:"about,attr"`
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Description string   `xml:"description"`
+	Content     string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Date        string   `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Creators    []string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Subjects    []string `xml:"http://purl.org/dc/elements/1.1/ subject"`
+}
+
+// RDFParser handles rss 1.0 documents
+type RDFParser struct{}
+
+func (RDFParser) Name() string {
+	return "rdf"
+}
+
+func (RDFParser) Detect(h Hint) bool {
+	return h.Root.Local == "RDF" && (h.Root.Space == "" || h.Root.Space == rdfNamespace)
+}
+
+func (RDFParser) Parse(body []byte) (*Feed, error) {
+	var doc rdfDocument
+	err := xml.Unmarshal(body, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("couldnt convert rdf xml into go struct: %w", err)
+	}
+	feed := &Feed{
+		Title:         doc.Channel.Title,
+		Link:          doc.Channel.Link,
+		Description:   doc.Channel.Description,
+		Language:      strings.TrimSpace(doc.Channel.Language),
+		Image:         strings.TrimSpace(doc.Image.URL),
+		LastBuildDate: strings.TrimSpace(doc.Channel.Date),
+		Schedule:      Schedule{Interval: doc.Channel.interval()},
+	}
+	for _, entry := range doc.Items {
+		item := Item{
+			Title:       entry.Title,
+			Link:        entry.Link,
+			Description: entry.Description,
+			Content:     strings.TrimSpace(entry.Content),
+			PubDate:     entry.Date,
+			GUID:        entry.About,
+			Authors:     cleanNames(entry.Creators),
+			Categories:  cleanNames(entry.Subjects),
+		}
+		// rdf:about is the canonical identifier and usually the link too
+		if item.Link == "" {
+			item.Link = entry.About
+		}
+		feed.Items = append(feed.Items, item)
+	}
+	unescape(feed)
+	return feed, nil
+}
@@ -0,0 +1,143 @@
+package feedparse
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+type rssDocument struct {
+	Channel struct {
+		syndicationFields
+		Title         string    `xml:"title"`
+		Links         []rssLink `xml:"link"`
+		Description   string    `xml:"description"`
+		Language      string    `xml:"language"`
+		Generator     string    `xml:"generator"`
+		LastBuildDate string    `xml:"lastBuildDate"`
+		// itunes:image has to come first, the untagged image field would
+		// take it otherwise
+		ItunesImage itunesImage `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+		Image       struct {
+			URL string `xml:"url"`
+		} `xml:"image"`
+		TTL       string    `xml:"ttl"`
+		SkipHours []string  `xml:"skipHours>hour"`
+		SkipDays  []string  `xml:"skipDays>day"`
+		Items     []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+// rss channels often carry atom:link elements next to the plain link, both
+// are collected so the right one can be picked
+type rssLink struct {
+	XMLName xml.Name
+	Href    string `xml:"href,attr"`
+	Rel     string `xml:"rel,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// siteLink returns the plain rss link, ignoring atom:link self references
+func siteLink(links []rssLink) string {
+	for _, link := range links {
+		if link.XMLName.Space != atomNamespace && strings.TrimSpace(link.Text) != "" {
+			return strings.TrimSpace(link.Text)
+		}
+	}
+	return ""
+}
+
+// relLink returns the href of the first link with the given rel
+func relLink(links []rssLink, rel string) string {
+	for _, link := range links {
+		if link.Rel == rel && link.Href != "" {
+			return strings.TrimSpace(link.Href)
+		}
+	}
+	return ""
+}
+
+type rssItem struct {
+	mediaFields
+	Title       string         `xml:"title"`
+	Link        string         `xml:"link"`
+	Description string         `xml:"description"`
+	Content     string         `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	PubDate     string         `xml:"pubDate"`
+	GUID        string         `xml:"guid"`
+	Author      string         `xml:"author"`
+	Creators    []string       `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Categories  []string       `xml:"category"`
+	Enclosures  []rssEnclosure `xml:"enclosure"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+// RSSParser handles rss 0.9x and 2.0 documents
+type RSSParser struct{}
+
+func (RSSParser) Name() string {
+	return "rss"
+}
+
+func (RSSParser) Detect(h Hint) bool {
+	if h.Root.Local == "rss" {
+		return true
+	}
+	// some servers label their feeds correctly but send a broken prolog
+	return h.Root.Local == "" && strings.Contains(h.ContentType, "rss+xml")
+}
+
+func (RSSParser) Parse(body []byte) (*Feed, error) {
+	var doc rssDocument
+	err := xml.Unmarshal(body, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("couldnt convert rss xml into go struct: %w", err)
+	}
+	feed := &Feed{
+		Title:         doc.Channel.Title,
+		Link:          siteLink(doc.Channel.Links),
+		Description:   doc.Channel.Description,
+		Language:      strings.TrimSpace(doc.Channel.Language),
+		Image:         strings.TrimSpace(doc.Channel.Image.URL),
+		Generator:     strings.TrimSpace(doc.Channel.Generator),
+		LastBuildDate: strings.TrimSpace(doc.Channel.LastBuildDate),
+		Schedule: Schedule{
+			Interval:  max(parseTTL(doc.Channel.TTL), doc.Channel.interval()),
+			SkipHours: parseSkipHours(doc.Channel.SkipHours),
+			SkipDays:  parseSkipDays(doc.Channel.SkipDays),
+		},
+		Hub:  relLink(doc.Channel.Links, "hub"),
+		Self: relLink(doc.Channel.Links, "self"),
+	}
+	if feed.Image == "" {
+		feed.Image = doc.Channel.ItunesImage.Href
+	}
+	for _, entry := range doc.Channel.Items {
+		item := Item{
+			Title:       entry.Title,
+			Link:        entry.Link,
+			Description: entry.Description,
+			Content:     strings.TrimSpace(entry.Content),
+			PubDate:     entry.PubDate,
+			GUID:        entry.GUID,
+			Authors:     cleanNames(append([]string{entry.Author}, entry.Creators...)),
+			Categories:  cleanNames(entry.Categories),
+		}
+		for _, enclosure := range entry.Enclosures {
+			item.Enclosures = append(item.Enclosures, Enclosure{
+				URL:    enclosure.URL,
+				Type:   enclosure.Type,
+				Length: parseLength(enclosure.Length),
+			})
+		}
+		item.Enclosures = entry.attachments(item.Enclosures)
+		feed.Items = append(feed.Items, item)
+	}
+	unescape(feed)
+	return feed, nil
+}
@@ -0,0 +1,110 @@
+package feedparse
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is what a feed says about how often it should be polled
+type Schedule struct {
+	// Interval is the publisher's suggested polling interval, zero when the
+	// feed gives no hint
+	Interval time.Duration
+	// SkipHours are hours of the day in utc when the feed should not be read
+	SkipHours []int
+	SkipDays  []time.Weekday
+}
+
+// syndicationFields collects the rss 1.0 syndication module, it is used by
+// rdf documents and shows up in plenty of rss 2.0 and atom feeds too
+type syndicationFields struct {
+	UpdatePeriod    string `xml:"http://purl.org/rss/1.0/modules/syndication/ updatePeriod"`
+	UpdateFrequency string `xml:"http://purl.org/rss/1.0/modules/syndication/ updateFrequency"`
+}
+
+var updatePeriods = map[string]time.Duration{
+	"hourly":  time.Hour,
+	"daily":   24 * time.Hour,
+	"weekly":  7 * 24 * time.Hour,
+	"monthly": 30 * 24 * time.Hour,
+	"yearly":  365 * 24 * time.Hour,
+}
+
+// interval spreads sy:updateFrequency updates over one sy:updatePeriod, the
+// module defaults to once a day when only one of the two is given
+func (s syndicationFields) interval() time.Duration {
+	period := strings.ToLower(strings.TrimSpace(s.UpdatePeriod))
+	frequency := strings.TrimSpace(s.UpdateFrequency)
+	if period == "" && frequency == "" {
+		return 0
+	}
+	length, ok := updatePeriods[period]
+	if !ok {
+		length = updatePeriods["daily"]
+	}
+	times, err := strconv.Atoi(frequency)
+	if err != nil || times < 1 {
+		times = 1
+	}
+	return length / time.Duration(times)
+}
+
+// parseTTL reads an rss ttl, given in minutes
+func parseTTL(raw string) time.Duration {
+	minutes, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil || minutes < 1 {
+		return 0
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// parseSkipHours keeps the valid hours of a skipHours element, 24 is
+// sometimes used for midnight
+func parseSkipHours(raw []string) []int {
+	var hours []int
+	seen := make(map[int]bool)
+	for _, value := range raw {
+		hour, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || hour < 0 || hour > 24 {
+			continue
+		}
+		hour %= 24
+		if !seen[hour] {
+			seen[hour] = true
+			hours = append(hours, hour)
+		}
+	}
+	return hours
+}
+
+func parseSkipDays(raw []string) []time.Weekday {
+	var days []time.Weekday
+	seen := make(map[time.Weekday]bool)
+	for _, value := range raw {
+		value = strings.TrimSpace(value)
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			if strings.EqualFold(value, day.String()) && !seen[day] {
+				seen[day] = true
+				days = append(days, day)
+			}
+		}
+	}
+	return days
+}
+
+// Skips reports whether the publisher asked not to be polled at t
+func (s Schedule) Skips(t time.Time) bool {
+	t = t.UTC()
+	for _, hour := range s.SkipHours {
+		if t.Hour() == hour {
+			return true
+		}
+	}
+	for _, day := range s.SkipDays {
+		if t.Weekday() == day {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,256 @@
+package feedparse
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Problem is one finding of Validate, Line is zero when it is not known
+type Problem struct {
+	Severity Severity
+	Line     int
+	Message  string
+}
+
+// Report is the result of validating a document
+type Report struct {
+	// Format is the name of the parser that accepted the document
+	Format   string
+	Feed     *Feed
+	Problems []Problem
+}
+
+func (r *Report) add(severity Severity, line int, format string, args ...any) {
+	r.Problems = append(r.Problems, Problem{Severity: severity, Line: line, Message: fmt.Sprintf(format, args...)})
+}
+
+// Errors counts the problems that keep a feed from working properly
+func (r Report) Errors() int {
+	count := 0
+	for _, problem := range r.Problems {
+		if problem.Severity == SeverityError {
+			count++
+		}
+	}
+	return count
+}
+
+// required channel and item elements per format, as named in their specs
+var requiredElements = map[string]struct {
+	channel []string
+	item    []string
+}{
+	"rss":  {channel: []string{"title", "link", "description"}, item: []string{"title|description"}},
+	"rdf":  {channel: []string{"title", "link", "description"}, item: []string{"title", "link"}},
+	"atom": {channel: []string{"title", "updated"}, item: []string{"title", "id", "updated"}},
+	"json": {channel: []string{"title"}, item: []string{"id"}},
+}
+
+// Validate parses a document like Parse does and reports what is wrong with
+// it. parseDate is the date parser the caller stores items with.
+func (r *Registry) Validate(contentType string, body []byte, parseDate func(string) error) Report {
+	var report Report
+	body, err := toUTF8(contentType, body)
+	if err != nil {
+		report.add(SeverityError, 0, "bad encoding: %v", err)
+		return report
+	}
+	if !utf8.Valid(body) {
+		offset := invalidUTF8Offset(body)
+		report.add(SeverityError, lineAt(body, offset), "bad encoding: invalid utf-8 at byte %d, is the declared charset right?", offset)
+		return report
+	}
+
+	if IsHTML(contentType, body) {
+		report.add(SeverityError, 0, "this is a web page, not a feed")
+		return report
+	}
+
+	var itemLines []int
+	isJSON := bytes.HasPrefix(bytes.TrimSpace(body), []byte("{"))
+	if isJSON {
+		if err := checkJSON(body); err != nil {
+			report.add(SeverityError, lineOf(body, err), "invalid json: %v", err)
+			return report
+		}
+	} else {
+		itemLines, err = checkXML(body)
+		if err != nil {
+			report.add(SeverityError, lineOf(body, err), "invalid xml: %v", err)
+			return report
+		}
+	}
+
+	p, err := r.Detect(contentType, body)
+	if err != nil {
+		report.add(SeverityError, 0, "%v", err)
+		return report
+	}
+	report.Format = p.Name()
+	feed, err := p.Parse(body)
+	if err != nil {
+		report.add(SeverityError, 0, "%v", err)
+		return report
+	}
+	report.Feed = feed
+	checkFeed(&report, feed, itemLines, parseDate)
+	return report
+}
+
+func Validate(contentType string, body []byte, parseDate func(string) error) Report {
+	return Default.Validate(contentType, body, parseDate)
+}
+
+// checkXML walks the whole document, which the unmarshalling in the parsers
+// does not always do, and returns the line of every item and entry
+func checkXML(body []byte) ([]int, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	var lines []int
+	for {
+		tok, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return lines, nil
+		}
+		if err != nil {
+			return lines, err
+		}
+		if start, ok := tok.(xml.StartElement); ok && (start.Name.Local == "item" || start.Name.Local == "entry") {
+			line, _ := decoder.InputPos()
+			lines = append(lines, line)
+		}
+	}
+}
+
+func checkJSON(body []byte) error {
+	var probe any
+	return json.Unmarshal(body, &probe)
+}
+
+// lineOf finds the line an xml or json syntax error points at
+func lineOf(body []byte, err error) int {
+	var xmlErr *xml.SyntaxError
+	if errors.As(err, &xmlErr) {
+		return xmlErr.Line
+	}
+	var jsonErr *json.SyntaxError
+	if errors.As(err, &jsonErr) {
+		return lineAt(body, int(jsonErr.Offset))
+	}
+	return 0
+}
+
+func lineAt(body []byte, offset int) int {
+	return bytes.Count(body[:min(offset, len(body))], []byte("\n")) + 1
+}
+
+func invalidUTF8Offset(body []byte) int {
+	for offset := 0; offset < len(body); {
+		r, size := utf8.DecodeRune(body[offset:])
+		if r == utf8.RuneError && size == 1 {
+			return offset
+		}
+		offset += size
+	}
+	return len(body)
+}
+
+// checkFeed looks for missing elements, bad dates, relative links and
+// duplicate guids in a parsed feed
+func checkFeed(report *Report, feed *Feed, itemLines []int, parseDate func(string) error) {
+	required := requiredElements[report.Format]
+	channel := map[string]string{
+		"title":       feed.Title,
+		"link":        feed.Link,
+		"description": feed.Description,
+		"updated":     feed.LastBuildDate,
+	}
+	for _, name := range required.channel {
+		if strings.TrimSpace(channel[name]) == "" {
+			report.add(SeverityError, 0, "feed is missing its %s", name)
+		}
+	}
+	if feed.LastBuildDate != "" && parseDate(feed.LastBuildDate) != nil {
+		report.add(SeverityWarning, 0, "feed date %q cannot be parsed", feed.LastBuildDate)
+	}
+	checkLink(report, 0, "feed link", feed.Link)
+	if len(feed.Items) == 0 {
+		report.add(SeverityWarning, 0, "feed has no items")
+	}
+
+	seen := make(map[string]int)
+	for i, item := range feed.Items {
+		line := 0
+		if i < len(itemLines) {
+			line = itemLines[i]
+		}
+		name := fmt.Sprintf("item %d", i+1)
+		if title := strings.TrimSpace(item.Title); title != "" {
+			name = fmt.Sprintf("item %d %q", i+1, title)
+		}
+		fields := map[string]string{
+			"title":       item.Title,
+			"link":        item.Link,
+			"description": item.Description,
+			"id":          item.GUID,
+			"updated":     item.Updated,
+		}
+		for _, element := range required.item {
+			present := false
+			for _, alternative := range strings.Split(element, "|") {
+				present = present || strings.TrimSpace(fields[alternative]) != ""
+			}
+			if !present {
+				report.add(SeverityError, line, "%s is missing its %s", name, strings.ReplaceAll(element, "|", " or "))
+			}
+		}
+		if item.PubDate == "" {
+			report.add(SeverityWarning, line, "%s has no date, the fetch time will be used", name)
+		} else if err := parseDate(item.PubDate); err != nil {
+			report.add(SeverityWarning, line, "%s has a date that cannot be parsed, the fetch time will be used: %q", name, item.PubDate)
+		}
+		if item.Link == "" && item.GUID == "" {
+			report.add(SeverityWarning, line, "%s has neither a link nor a guid and cannot be told apart from other items", name)
+		}
+		checkLink(report, line, name+" link", item.Link)
+		for _, enclosure := range item.Enclosures {
+			checkLink(report, line, name+" enclosure", enclosure.URL)
+		}
+		if guid := strings.TrimSpace(item.GUID); guid != "" {
+			if first, ok := seen[guid]; ok {
+				report.add(SeverityError, line, "%s repeats the guid %q of item %d, only one of them will be stored", name, guid, first+1)
+			} else {
+				seen[guid] = i
+			}
+		}
+	}
+}
+
+// checkLink reports links that readers cannot follow on their own
+func checkLink(report *Report, line int, what, link string) {
+	link = strings.TrimSpace(link)
+	if link == "" {
+		return
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		report.add(SeverityError, line, "%s %q is not a valid url: %v", what, link, err)
+		return
+	}
+	if !u.IsAbs() {
+		report.add(SeverityWarning, line, "%s %q is relative", what, link)
+	}
+}
@@ -0,0 +1,80 @@
+package feedparse
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func parseRFC3339(value string) error {
+	_, err := time.Parse(time.RFC3339, value)
+	return err
+}
+
+func TestValidateAtomEntries(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry string
+		want  []string
+	}{
+		{
+			name:  "complete",
+			entry: `<entry><title>A</title><id>urn:a</id><link href="https://example.com/a"/><updated>2024-01-01T10:00:00Z</updated></entry>`,
+		},
+		{
+			name:  "published but no updated",
+			entry: `<entry><title>A</title><id>urn:a</id><link href="https://example.com/a"/><published>2024-01-01T10:00:00Z</published></entry>`,
+			want:  []string{`item 1 "A" is missing its updated`},
+		},
+		{
+			name:  "no id",
+			entry: `<entry><title>A</title><link href="https://example.com/a"/><updated>2024-01-01T10:00:00Z</updated></entry>`,
+			want:  []string{`item 1 "A" is missing its id`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `<feed xmlns="http://www.w3.org/2005/Atom"><title>Feed</title><updated>2024-01-01T10:00:00Z</updated>` + tt.entry + `</feed>`
+			report := Validate("application/atom+xml", []byte(body), parseRFC3339)
+			if report.Format != "atom" {
+				t.Fatalf("format = %q, problems %+v", report.Format, report.Problems)
+			}
+			var errs []string
+			for _, problem := range report.Problems {
+				if problem.Severity == SeverityError {
+					errs = append(errs, problem.Message)
+				}
+			}
+			if strings.Join(errs, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("errors = %q, want %q", errs, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateReportsBrokenDocuments(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+		line        int
+	}{
+		{"bad xml", "application/rss+xml", "<rss>\n<channel>\n<title>x</channel></rss>", "invalid xml", 3},
+		{"bad json", "application/feed+json", "{\n\"version\": }", "invalid json", 2},
+		{"web page", "text/html", "<html></html>", "this is a web page", 0},
+		{"bad utf-8", "application/rss+xml; charset=utf-8", "<rss>\n\xff</rss>", "bad encoding", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Validate(tt.contentType, []byte(tt.body), func(string) error { return errors.New("unused") })
+			if report.Errors() != 1 || !strings.HasPrefix(report.Problems[0].Message, tt.want) {
+				t.Fatalf("problems = %+v, want one starting with %q", report.Problems, tt.want)
+			}
+			if report.Problems[0].Line != tt.line {
+				t.Errorf("line = %d, want %d", report.Problems[0].Line, tt.line)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/Uttam1916/Gator/internal/config"
+)
+
+// defaults used when the config leaves a setting out
+const (
+	DefaultTimeout      = 30 * time.Second
+	DefaultMaxBodyBytes = 10 << 20
+	DefaultUserAgent    = "gator"
+)
+
+// ErrBodyTooLarge is returned when a response exceeds the configured limit
+var ErrBodyTooLarge = errors.New("response body too large")
+
+type Options struct {
+	Timeout      time.Duration
+	MaxBodyBytes int64
+	UserAgent    string
+	// ContactURL is appended to the user agent so publishers can reach us
+	ContactURL string
+	// ProxyURL overrides the proxy taken from the environment
+	ProxyURL string
+	// CABundle is a pem file trusted in addition to the system roots
+	CABundle string
+}
+
+// Client is shared by every fetch so connections are reused across feeds
+type Client struct {
+	HTTP         *http.Client
+	UserAgent    string
+	MaxBodyBytes int64
+}
+
+// FromConfig builds a client from the fetch settings in the gator config
+func FromConfig(c config.Config) (*Client, error) {
+	opts := Options{
+		MaxBodyBytes: c.Max_body_bytes,
+		UserAgent:    c.User_agent,
+		ContactURL:   c.Contact_url,
+		ProxyURL:     c.Proxy_url,
+		CABundle:     c.Ca_bundle,
+	}
+	if c.Fetch_timeout != "" {
+		timeout, err := time.ParseDuration(c.Fetch_timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fetch_timeout: %v", err)
+		}
+		opts.Timeout = timeout
+	}
+	return New(opts)
+}
+
+func New(opts Options) (*Client, error) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.MaxBodyBytes <= 0 {
+		opts.MaxBodyBytes = DefaultMaxBodyBytes
+	}
+	if opts.UserAgent == "" {
+		opts.UserAgent = DefaultUserAgent
+	}
+	if opts.ContactURL != "" {
+		opts.UserAgent = fmt.Sprintf("%s (+%s)", opts.UserAgent, opts.ContactURL)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = 4
+	if opts.ProxyURL != "" {
+		proxy, err := url.Parse(opts.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy_url: %v", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	if opts.CABundle != "" {
+		pool, err := loadCABundle(opts.CABundle)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return &Client{
+		HTTP: &http.Client{
+			Timeout:   opts.Timeout,
+			Transport: transport,
+		},
+		UserAgent:    opts.UserAgent,
+		MaxBodyBytes: opts.MaxBodyBytes,
+	}, nil
+}
+
+// NewRequest creates a GET request carrying the configured user agent
+func (c *Client) NewRequest(ctx context.Context, rawURL string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", c.UserAgent)
+	return req, nil
+}
+
+// ReadBody reads at most MaxBodyBytes from a response
+func (c *Client) ReadBody(resp *http.Response) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, c.MaxBodyBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > c.MaxBodyBytes {
+		return nil, fmt.Errorf("%w: more than %d bytes", ErrBodyTooLarge, c.MaxBodyBytes)
+	}
+	return body, nil
+}
+
+func loadCABundle(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("couldnt read ca_bundle: %v", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return pool, nil
+}
@@ -0,0 +1,170 @@
+package localfeed
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Uttam1916/Gator/internal/httpclient"
+)
+
+// ErrUnknownCommand is returned for exec: feeds missing from exec_commands
+var ErrUnknownCommand = errors.New("command is not listed in exec_commands")
+
+// IsLocal reports whether a feed url is read from disk or a command instead
+// of over http
+func IsLocal(rawURL string) bool {
+	return strings.HasPrefix(rawURL, "file:") || strings.HasPrefix(rawURL, "exec:")
+}
+
+// Source reads local feeds. Commands maps the name in an exec:name url to
+// the program and arguments to run, so feeds can only run what the config
+// allows.
+type Source struct {
+	Commands     map[string][]string
+	MaxBodyBytes int64
+	Timeout      time.Duration
+}
+
+// Result is a local feed document
+type Result struct {
+	Body        []byte
+	ContentType string
+	// ModTime is the file's modification time, zero for commands
+	ModTime time.Time
+}
+
+func (s Source) Read(ctx context.Context, rawURL string) (Result, error) {
+	if name, ok := strings.CutPrefix(rawURL, "exec:"); ok {
+		return s.run(ctx, name)
+	}
+	path, err := FilePath(rawURL)
+	if err != nil {
+		return Result{}, err
+	}
+	return s.readFile(path)
+}
+
+// FilePath returns the local path of a file:// url
+func FilePath(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid file url: %v", err)
+	}
+	if u.Scheme != "file" {
+		return "", fmt.Errorf("not a file url: %s", rawURL)
+	}
+	if u.Host != "" && u.Host != "localhost" {
+		return "", fmt.Errorf("file urls must point to this machine, got host %s", u.Host)
+	}
+	if u.Path == "" {
+		return "", fmt.Errorf("file url has no path: %s", rawURL)
+	}
+	return filepath.FromSlash(u.Path), nil
+}
+
+func (s Source) readFile(path string) (Result, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Result{}, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return Result{}, err
+	}
+	if info.IsDir() {
+		return Result{}, fmt.Errorf("%s is a directory", path)
+	}
+	body, err := s.limit(file)
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{
+		Body:        body,
+		ContentType: mime.TypeByExtension(filepath.Ext(path)),
+		ModTime:     info.ModTime(),
+	}, nil
+}
+
+// run executes a configured command and returns what it printed
+func (s Source) run(ctx context.Context, name string) (Result, error) {
+	argv, ok := s.Commands[name]
+	if !ok || len(argv) == 0 {
+		return Result{}, fmt.Errorf("%w: %s", ErrUnknownCommand, name)
+	}
+	if s.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.Timeout)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	// children of the command may keep its output open after it is killed
+	cmd.WaitDelay = time.Second
+	var stderr bytes.Buffer
+	cmd.Stderr = &limitedWriter{w: &stderr, n: 1024}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return Result{}, err
+	}
+	if err := cmd.Start(); err != nil {
+		return Result{}, fmt.Errorf("couldnt start %s: %v", argv[0], err)
+	}
+	body, readErr := s.limit(stdout)
+	if readErr != nil {
+		// stop the command instead of waiting for it to finish writing
+		cmd.Process.Kill()
+	}
+	err = cmd.Wait()
+	if ctx.Err() != nil {
+		return Result{}, fmt.Errorf("%s did not finish in time: %v", name, ctx.Err())
+	}
+	if readErr != nil {
+		return Result{}, readErr
+	}
+	if err != nil {
+		return Result{}, fmt.Errorf("%s failed: %v %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+	return Result{Body: body}, nil
+}
+
+func (s Source) limit(r io.Reader) ([]byte, error) {
+	if s.MaxBodyBytes <= 0 {
+		return io.ReadAll(r)
+	}
+	body, err := io.ReadAll(io.LimitReader(r, s.MaxBodyBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > s.MaxBodyBytes {
+		return nil, fmt.Errorf("%w: more than %d bytes", httpclient.ErrBodyTooLarge, s.MaxBodyBytes)
+	}
+	return body, nil
+}
+
+// limitedWriter keeps the first n bytes and drops the rest
+type limitedWriter struct {
+	w io.Writer
+	n int
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if l.n > 0 {
+		keep := p
+		if len(keep) > l.n {
+			keep = keep[:l.n]
+		}
+		l.n -= len(keep)
+		l.w.Write(keep)
+	}
+	return len(p), nil
+}
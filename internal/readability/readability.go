@@ -0,0 +1,235 @@
+package readability
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// ErrNoContent is returned when no part of the page looks like an article
+var ErrNoContent = errors.New("no article content found")
+
+var (
+	positiveHints = regexp.MustCompile(`(?i)article|body|content|entry|hentry|main|page|post|text|blog|story`)
+	negativeHints = regexp.MustCompile(`(?i)comment|meta|footer|footnote|masthead|sidebar|sponsor|widget|promo|related|share|social|banner|advert|\bads?\b|popup|menu|nav|breadcrumb|subscribe|newsletter|cookie`)
+)
+
+// elements that never hold article text
+var strippedTags = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Nav:      true,
+	atom.Header:   true,
+	atom.Footer:   true,
+	atom.Aside:    true,
+	atom.Form:     true,
+	atom.Iframe:   true,
+	atom.Button:   true,
+	atom.Svg:      true,
+}
+
+// minimum length for a paragraph to count towards its parent's score
+const minParagraphLength = 25
+
+// Extract finds the main article in an html page and returns it as html.
+// Relative links and images are resolved against pageURL.
+func Extract(pageURL string, body []byte) (string, error) {
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	strip(doc)
+
+	scores := &candidates{scores: make(map[*html.Node]float64)}
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && (n.DataAtom == atom.P || n.DataAtom == atom.Pre || n.DataAtom == atom.Td) {
+			scoreParagraph(n, scores)
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+
+	var best *html.Node
+	bestScore := 0.0
+	// ties go to the candidate found first so the same page always gives
+	// the same article
+	for _, node := range scores.order {
+		// long runs of links are navigation, not prose
+		score := scores.scores[node] * (1 - linkDensity(node))
+		if score > bestScore {
+			best, bestScore = node, score
+		}
+	}
+	if best == nil {
+		return "", ErrNoContent
+	}
+
+	if base, err := url.Parse(pageURL); err == nil {
+		absolutize(best, base)
+	}
+	var out bytes.Buffer
+	for child := best.FirstChild; child != nil; child = child.NextSibling {
+		if err := html.Render(&out, child); err != nil {
+			return "", err
+		}
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+// strip removes boilerplate elements and anything whose class or id marks
+// it as navigation or advertising
+func strip(n *html.Node) {
+	for child := n.FirstChild; child != nil; {
+		next := child.NextSibling
+		if child.Type == html.CommentNode || (child.Type == html.ElementNode && isBoilerplate(child)) {
+			n.RemoveChild(child)
+		} else {
+			strip(child)
+		}
+		child = next
+	}
+}
+
+func isBoilerplate(n *html.Node) bool {
+	if strippedTags[n.DataAtom] {
+		return true
+	}
+	if n.DataAtom == atom.Body || n.DataAtom == atom.Html || n.DataAtom == atom.Article || n.DataAtom == atom.Main {
+		return false
+	}
+	hints := classAndID(n)
+	return negativeHints.MatchString(hints) && !positiveHints.MatchString(hints)
+}
+
+// candidates holds the score of every node a paragraph credited, order
+// keeps them in the order they were first reached while walking the page
+type candidates struct {
+	scores map[*html.Node]float64
+	order  []*html.Node
+}
+
+func (c *candidates) add(n *html.Node, score float64) {
+	if _, ok := c.scores[n]; !ok {
+		c.scores[n] = initialScore(n)
+		c.order = append(c.order, n)
+	}
+	c.scores[n] += score
+}
+
+// scoreParagraph credits a paragraph's parent fully and its grandparent by
+// half, the way readability implementations have done since arc90
+func scoreParagraph(p *html.Node, scores *candidates) {
+	text := strings.TrimSpace(textContent(p))
+	if len(text) < minParagraphLength {
+		return
+	}
+	score := 1 + float64(strings.Count(text, ",")) + math.Min(float64(len(text))/100, 3)
+
+	parent := p.Parent
+	if parent == nil || parent.Type != html.ElementNode {
+		return
+	}
+	scores.add(parent, score)
+
+	grandparent := parent.Parent
+	if grandparent == nil || grandparent.Type != html.ElementNode {
+		return
+	}
+	scores.add(grandparent, score/2)
+}
+
+func initialScore(n *html.Node) float64 {
+	var score float64
+	switch n.DataAtom {
+	case atom.Article, atom.Main:
+		score += 10
+	case atom.Div:
+		score += 5
+	case atom.Pre, atom.Td, atom.Blockquote:
+		score += 3
+	case atom.Ol, atom.Ul, atom.Dl, atom.Form:
+		score -= 3
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Th:
+		score -= 5
+	}
+	hints := classAndID(n)
+	if positiveHints.MatchString(hints) {
+		score += 25
+	}
+	if negativeHints.MatchString(hints) {
+		score -= 25
+	}
+	return score
+}
+
+// linkDensity is the share of a node's text that sits inside links
+func linkDensity(n *html.Node) float64 {
+	total := len(textContent(n))
+	if total == 0 {
+		return 0
+	}
+	var linked int
+	var walk func(*html.Node)
+	walk = func(c *html.Node) {
+		if c.Type == html.ElementNode && c.DataAtom == atom.A {
+			linked += len(textContent(c))
+			return
+		}
+		for child := c.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+	return float64(linked) / float64(total)
+}
+
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(c *html.Node) {
+		if c.Type == html.TextNode {
+			b.WriteString(c.Data)
+		}
+		for child := c.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+	return b.String()
+}
+
+func classAndID(n *html.Node) string {
+	var hints []string
+	for _, attr := range n.Attr {
+		if attr.Key == "class" || attr.Key == "id" {
+			hints = append(hints, attr.Val)
+		}
+	}
+	return strings.Join(hints, " ")
+}
+
+func absolutize(n *html.Node, base *url.URL) {
+	if n.Type == html.ElementNode {
+		for i, attr := range n.Attr {
+			if attr.Key != "href" && attr.Key != "src" {
+				continue
+			}
+			if ref, err := url.Parse(strings.TrimSpace(attr.Val)); err == nil {
+				n.Attr[i].Val = base.ResolveReference(ref).String()
+			}
+		}
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		absolutize(child, base)
+	}
+}
@@ -0,0 +1,63 @@
+package readability
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	body, err := os.ReadFile("testdata/article.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	article, err := Extract("https://example.com/2024/01/post.html", body)
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	for _, want := range []string{
+		"Feeds let readers follow sites",
+		"Every reader decides for themselves",
+		`href="https://example.com/notes/1"`,
+		`src="https://example.com/2024/01/images/chart.png"`,
+	} {
+		if !strings.Contains(article, want) {
+			t.Errorf("article is missing %q:\n%s", want, article)
+		}
+	}
+	for _, unwanted := range []string{"tracking", "Archive", "newsletter", "First comment", "Copyright"} {
+		if strings.Contains(article, unwanted) {
+			t.Errorf("article contains boilerplate %q:\n%s", unwanted, article)
+		}
+	}
+}
+
+func TestExtractWithoutArticle(t *testing.T) {
+	tests := []string{
+		``,
+		`<html><body><nav><a href="/">Home</a></nav></body></html>`,
+		`<html><body><p>Too short.</p></body></html>`,
+	}
+	for _, page := range tests {
+		if _, err := Extract("https://example.com/", []byte(page)); !errors.Is(err, ErrNoContent) {
+			t.Errorf("Extract(%q) = %v, want ErrNoContent", page, err)
+		}
+	}
+}
+
+func TestExtractTiesGoToTheFirstCandidate(t *testing.T) {
+	page := `<html><body>
+<section><div><p>Alpha paragraph that is long enough to be scored, with a comma.</p></div></section>
+<section><div><p>Bravo paragraph that is long enough to be scored, with a comma.</p></div></section>
+</body></html>`
+	for i := 0; i < 20; i++ {
+		article, err := Extract("https://example.com/", []byte(page))
+		if err != nil {
+			t.Fatalf("Extract: %v", err)
+		}
+		if !strings.Contains(article, "Alpha") || strings.Contains(article, "Bravo") {
+			t.Fatalf("run %d picked the wrong block:\n%s", i, article)
+		}
+	}
+}
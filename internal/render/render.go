@@ -0,0 +1,320 @@
+package render
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"golang.org/x/term"
+)
+
+// DefaultWidth is used when the terminal size cannot be determined
+const DefaultWidth = 80
+
+// elements that carry nothing worth showing in a terminal
+var droppedTags = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Iframe:   true,
+	atom.Object:   true,
+	atom.Embed:    true,
+	atom.Form:     true,
+	atom.Button:   true,
+	atom.Svg:      true,
+	atom.Head:     true,
+}
+
+// TerminalWidth returns the width of stdout, falling back to $COLUMNS and
+// then DefaultWidth
+func TerminalWidth() int {
+	if width, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && width > 0 {
+		return width
+	}
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+	return DefaultWidth
+}
+
+// Text converts post html into wrapped plain text. Links are replaced by
+// numbered footnotes listed at the end.
+func Text(src string, width int) string {
+	if width < 20 {
+		width = 20
+	}
+	doc, err := html.Parse(strings.NewReader(src))
+	if err != nil {
+		return sanitize(src)
+	}
+	r := &renderer{width: width}
+	r.walk(doc)
+	r.flush()
+	if len(r.links) > 0 {
+		r.blank()
+		for i, link := range r.links {
+			r.emit(fmt.Sprintf("[%d] %s", i+1, link))
+		}
+	}
+	return strings.TrimRight(strings.Join(r.lines, "\n"), "\n ")
+}
+
+type renderer struct {
+	width  int
+	lines  []string
+	inline strings.Builder
+	// prefix holds the indentation of every open block, marker replaces
+	// the innermost one on the next line written (list bullets)
+	prefix []string
+	marker string
+	links  []string
+	// lists counts the lists we are inside, nested ones stay compact
+	lists int
+}
+
+func (r *renderer) walk(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		r.inline.WriteString(sanitize(n.Data))
+		return
+	case html.ElementNode:
+		if droppedTags[n.DataAtom] {
+			return
+		}
+	case html.CommentNode, html.DoctypeNode:
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.Br:
+		r.flush()
+		return
+	case atom.Hr:
+		r.flush()
+		r.blank()
+		r.emit(strings.Repeat("─", min(r.width-r.indent(), 40)))
+		r.blank()
+		return
+	case atom.Img:
+		if alt := strings.TrimSpace(attr(n, "alt")); alt != "" {
+			r.inline.WriteString(" [image: " + sanitize(alt) + "] ")
+		} else {
+			r.inline.WriteString(" [image] ")
+		}
+		return
+	case atom.Pre:
+		r.flush()
+		r.blank()
+		r.push("    ")
+		for _, line := range strings.Split(strings.TrimRight(sanitizeBlock(textContent(n)), "\n"), "\n") {
+			r.emit(line)
+		}
+		r.pop()
+		r.blank()
+		return
+	case atom.Code:
+		r.inline.WriteString("`")
+		r.children(n)
+		r.inline.WriteString("`")
+		return
+	case atom.A:
+		r.children(n)
+		href := strings.TrimSpace(attr(n, "href"))
+		if href != "" && !strings.HasPrefix(href, "#") && !strings.HasPrefix(strings.ToLower(href), "javascript:") {
+			r.links = append(r.links, sanitize(href))
+			r.inline.WriteString(fmt.Sprintf("[%d]", len(r.links)))
+		}
+		return
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		r.flush()
+		r.blank()
+		level := int(n.Data[1] - '0')
+		r.inline.WriteString(strings.Repeat("#", level) + " ")
+		r.children(n)
+		r.flush()
+		r.blank()
+		return
+	case atom.Blockquote:
+		r.flush()
+		r.blank()
+		r.push("│ ")
+		r.children(n)
+		r.flush()
+		r.pop()
+		r.blank()
+		return
+	case atom.Ul, atom.Ol:
+		r.flush()
+		if r.lists == 0 {
+			r.blank()
+		}
+		r.lists++
+		number := 1
+		if start, err := strconv.Atoi(attr(n, "start")); err == nil {
+			number = start
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode || child.DataAtom != atom.Li {
+				continue
+			}
+			marker := "• "
+			if n.DataAtom == atom.Ol {
+				marker = strconv.Itoa(number) + ". "
+				number++
+			}
+			r.push(strings.Repeat(" ", utf8.RuneCountInString(marker)))
+			r.marker = marker
+			r.children(child)
+			r.flush()
+			r.marker = ""
+			r.pop()
+		}
+		r.lists--
+		if r.lists == 0 {
+			r.blank()
+		}
+		return
+	case atom.P, atom.Div, atom.Section, atom.Article, atom.Figure, atom.Figcaption,
+		atom.Table, atom.Tr, atom.Dl, atom.Dt, atom.Dd, atom.Li:
+		// paragraphs are spaced out, except inside compact lists
+		spaced := n.DataAtom == atom.P && r.lists == 0
+		r.flush()
+		if spaced {
+			r.blank()
+		}
+		r.children(n)
+		r.flush()
+		if spaced {
+			r.blank()
+		}
+		return
+	case atom.Td, atom.Th:
+		r.children(n)
+		r.inline.WriteString("  ")
+		return
+	}
+	r.children(n)
+}
+
+func (r *renderer) children(n *html.Node) {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		r.walk(child)
+	}
+}
+
+func (r *renderer) push(indent string) {
+	r.prefix = append(r.prefix, indent)
+}
+
+func (r *renderer) pop() {
+	r.prefix = r.prefix[:len(r.prefix)-1]
+}
+
+func (r *renderer) indent() int {
+	return utf8.RuneCountInString(strings.Join(r.prefix, ""))
+}
+
+// emit writes one finished line under the current prefix
+func (r *renderer) emit(line string) {
+	prefix := strings.Join(r.prefix, "")
+	if r.marker != "" && len(r.prefix) > 0 {
+		prefix = strings.Join(r.prefix[:len(r.prefix)-1], "") + r.marker
+		r.marker = ""
+	}
+	r.lines = append(r.lines, strings.TrimRight(prefix+line, " "))
+}
+
+// blank separates blocks with a single empty line
+func (r *renderer) blank() {
+	if len(r.lines) == 0 || r.lines[len(r.lines)-1] == "" {
+		return
+	}
+	r.lines = append(r.lines, "")
+}
+
+// flush wraps the pending inline text into lines
+func (r *renderer) flush() {
+	words := strings.Fields(r.inline.String())
+	r.inline.Reset()
+	if len(words) == 0 {
+		return
+	}
+	available := max(r.width-r.indent(), 10)
+	var line strings.Builder
+	length := 0
+	for _, word := range words {
+		wordLength := utf8.RuneCountInString(word)
+		if length > 0 && length+1+wordLength > available {
+			r.emit(line.String())
+			line.Reset()
+			length = 0
+		}
+		if length > 0 {
+			line.WriteByte(' ')
+			length++
+		}
+		line.WriteString(word)
+		length += wordLength
+	}
+	r.emit(line.String())
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(c *html.Node) {
+		if c.Type == html.TextNode {
+			b.WriteString(c.Data)
+		}
+		if c.Type == html.ElementNode && c.DataAtom == atom.Br {
+			b.WriteByte('\n')
+		}
+		for child := c.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+	return b.String()
+}
+
+// sanitize drops control characters so feed content cannot send escape
+// sequences to the terminal
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' {
+			return ' '
+		}
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// sanitizeBlock is sanitize for preformatted text, keeping line breaks
+func sanitizeBlock(s string) string {
+	s = strings.ReplaceAll(s, "\t", "    ")
+	return strings.Map(func(r rune) rune {
+		if r == '\n' {
+			return r
+		}
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
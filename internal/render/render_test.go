@@ -0,0 +1,85 @@
+package render
+
+import "testing"
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "wrapping",
+			html: `<p>The quick brown fox jumps over the lazy dog.</p>`,
+			want: "The quick brown fox\njumps over the lazy\ndog.",
+		},
+		{
+			name: "paragraphs and headings",
+			html: `<h2>Title</h2><p>One.</p><p>Two.</p>`,
+			want: "## Title\n\nOne.\n\nTwo.",
+		},
+		{
+			name: "unordered list",
+			html: `<ul><li>first item that wraps around</li><li>second</li></ul>`,
+			want: "• first item that\n  wraps around\n• second",
+		},
+		{
+			name: "ordered list with start",
+			html: `<ol start="3"><li>three</li><li>four</li></ol>`,
+			want: "3. three\n4. four",
+		},
+		{
+			name: "nested list",
+			html: `<ul><li>outer<ul><li>inner</li></ul></li></ul><p>after</p>`,
+			want: "• outer\n  • inner\n\nafter",
+		},
+		{
+			name: "blockquote",
+			html: `<p>He said:</p><blockquote><p>Keep it simple, always.</p></blockquote>`,
+			want: "He said:\n\n│ Keep it simple,\n│ always.",
+		},
+		{
+			name: "pre keeps its lines",
+			html: "<pre>if x {\n\treturn y\n}</pre>",
+			want: "    if x {\n        return y\n    }",
+		},
+		{
+			name: "link footnotes",
+			html: `<p>See <a href="https://a.example/">this</a>, <a href="#top">top</a> and <a href="javascript:void(0)">that</a>.</p>`,
+			want: "See this[1], top and\nthat.\n\n[1] https://a.example/",
+		},
+		{
+			name: "images and code",
+			html: `<p><img src="x.png" alt="A cat"> run <code>go test</code></p>`,
+			want: "[image: A cat] run\n`go test`",
+		},
+		{
+			name: "dropped elements",
+			html: `<script>alert(1)</script><style>p{}</style><p>kept</p>`,
+			want: "kept",
+		},
+		{
+			name: "control characters are removed",
+			html: "<p>red \x1b[31mtext\x1b[0m\x07 bell</p>",
+			want: "red [31mtext[0m bell",
+		},
+		{
+			name: "control characters in links and pre",
+			html: "<pre>a\x1b]0;title\x07b</pre><a href=\"https://x.example/\x1b[2J\">x</a>",
+			want: "    a]0;titleb\n\nx[1]\n\n[1] https://x.example/[2J",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Text(tt.html, 20); got != tt.want {
+				t.Errorf("Text() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextMinimumWidth(t *testing.T) {
+	if got, want := Text("<p>one two three four five six</p>", 5), Text("<p>one two three four five six</p>", 20); got != want {
+		t.Errorf("Text at width 5 =\n%s\nwant the width 20 output\n%s", got, want)
+	}
+}
@@ -0,0 +1,219 @@
+package websub
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Uttam1916/Gator/internal/httpclient"
+)
+
+// ErrUnknownSubscription is returned by a Lookup for callbacks we never handed out
+var ErrUnknownSubscription = errors.New("unknown subscription")
+
+// SubscribeRequest is sent to a hub to start or renew a subscription
+type SubscribeRequest struct {
+	Hub      string
+	Topic    string
+	Callback string
+	Secret   string
+	// Lease is the lease we ask for, the hub has the final say
+	Lease time.Duration
+}
+
+// Subscribe asks a hub to push updates of a topic to our callback. The hub
+// answers 202 and confirms later by calling the callback with a challenge.
+func Subscribe(ctx context.Context, client *httpclient.Client, r SubscribeRequest) error {
+	return send(ctx, client, "subscribe", r)
+}
+
+// Unsubscribe asks a hub to stop pushing a topic to our callback
+func Unsubscribe(ctx context.Context, client *httpclient.Client, r SubscribeRequest) error {
+	return send(ctx, client, "unsubscribe", r)
+}
+
+func send(ctx context.Context, client *httpclient.Client, mode string, r SubscribeRequest) error {
+	form := url.Values{
+		"hub.mode":     {mode},
+		"hub.topic":    {r.Topic},
+		"hub.callback": {r.Callback},
+	}
+	if r.Secret != "" {
+		form.Set("hub.secret", r.Secret)
+	}
+	if r.Lease > 0 {
+		form.Set("hub.lease_seconds", strconv.Itoa(int(r.Lease/time.Second)))
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", r.Hub, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("couldnt form request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", client.UserAgent)
+	resp, err := client.HTTP.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending %s request: %v", mode, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusNoContent {
+		// hubs explain refusals in the body, keep a little of it
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("hub refused %s: %s %s", mode, resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// NewSecret returns a random secret for signing pushed content
+func NewSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// VerifySignature checks an X-Hub-Signature header such as "sha256=abc..."
+// against the body
+func VerifySignature(secret string, body []byte, header string) bool {
+	method, signature, ok := strings.Cut(strings.TrimSpace(header), "=")
+	if !ok {
+		return false
+	}
+	var mac hash.Hash
+	switch strings.ToLower(method) {
+	case "sha1":
+		mac = hmac.New(sha1.New, []byte(secret))
+	case "sha256":
+		mac = hmac.New(sha256.New, []byte(secret))
+	case "sha384":
+		mac = hmac.New(sha512.New384, []byte(secret))
+	case "sha512":
+		mac = hmac.New(sha512.New, []byte(secret))
+	default:
+		return false
+	}
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// Subscription is what the callback needs to know about a subscription
+type Subscription struct {
+	ID     string
+	Topic  string
+	Secret string
+}
+
+// Handler serves the callback urls handed to hubs, the last path segment
+// identifies the subscription
+type Handler struct {
+	// Lookup finds a subscription, returning ErrUnknownSubscription when
+	// there is none
+	Lookup func(ctx context.Context, id string) (Subscription, error)
+	// Verified is called once a hub confirms a subscription or reports
+	// that it was denied, lease is zero for denials
+	Verified func(ctx context.Context, sub Subscription, mode string, lease time.Duration) error
+	// Deliver receives content pushed for a verified subscription
+	Deliver func(ctx context.Context, sub Subscription, contentType string, body []byte) error
+	// MaxBodyBytes limits pushed content
+	MaxBodyBytes int64
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	switch r.Method {
+	case http.MethodGet:
+		h.verify(w, r, id)
+	case http.MethodPost:
+		h.deliver(w, r, id)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// verify answers a hub's intent verification by echoing the challenge
+func (h *Handler) verify(w http.ResponseWriter, r *http.Request, id string) {
+	query := r.URL.Query()
+	mode := query.Get("hub.mode")
+	topic := query.Get("hub.topic")
+	sub, err := h.Lookup(r.Context(), id)
+	if errors.Is(err, ErrUnknownSubscription) {
+		// we forget subscriptions we no longer want, so confirm dropping them
+		if mode == "unsubscribe" {
+			io.WriteString(w, query.Get("hub.challenge"))
+			return
+		}
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, "lookup failed", http.StatusInternalServerError)
+		return
+	}
+	if topic != sub.Topic {
+		http.NotFound(w, r)
+		return
+	}
+	switch mode {
+	case "subscribe":
+		seconds, _ := strconv.Atoi(query.Get("hub.lease_seconds"))
+		if err := h.Verified(r.Context(), sub, mode, time.Duration(seconds)*time.Second); err != nil {
+			http.Error(w, "could not record subscription", http.StatusInternalServerError)
+			return
+		}
+		io.WriteString(w, query.Get("hub.challenge"))
+	case "denied":
+		if err := h.Verified(r.Context(), sub, mode, 0); err != nil {
+			http.Error(w, "could not record denial", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	default:
+		// we still want this topic, refuse to unsubscribe
+		http.NotFound(w, r)
+	}
+}
+
+// deliver passes pushed content on. Hubs retry anything but a 2xx, so
+// content with a bad signature is acknowledged and dropped as the spec asks.
+func (h *Handler) deliver(w http.ResponseWriter, r *http.Request, id string) {
+	sub, err := h.Lookup(r.Context(), id)
+	if errors.Is(err, ErrUnknownSubscription) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, "lookup failed", http.StatusInternalServerError)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, h.MaxBodyBytes))
+	if err != nil {
+		http.Error(w, "body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if sub.Secret != "" && !VerifySignature(sub.Secret, body, r.Header.Get("X-Hub-Signature")) {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	if err := h.Deliver(r.Context(), sub, r.Header.Get("Content-Type"), body); err != nil {
+		http.Error(w, "could not store content", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
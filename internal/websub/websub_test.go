@@ -0,0 +1,247 @@
+package websub
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Uttam1916/Gator/internal/httpclient"
+)
+
+func sign(newHash func() hash.Hash, secret, body string) string {
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySignature(t *testing.T) {
+	const secret, body = "s3cret", "<feed/>"
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{"sha1", "sha1=" + sign(sha1.New, secret, body), true},
+		{"sha256", "sha256=" + sign(sha256.New, secret, body), true},
+		{"method is case insensitive", "SHA256=" + sign(sha256.New, secret, body), true},
+		{"wrong secret", "sha256=" + sign(sha256.New, "other", body), false},
+		{"wrong body", "sha256=" + sign(sha256.New, secret, "<rss/>"), false},
+		{"unknown method", "md5=" + sign(sha256.New, secret, body), false},
+		{"not hex", "sha256=zz", false},
+		{"missing method", sign(sha256.New, secret, body), false},
+		{"empty", "", false},
+	}
+	for _, tt := range tests {
+		if got := VerifySignature(secret, []byte(body), tt.header); got != tt.want {
+			t.Errorf("%s: VerifySignature = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// testHandler serves one known subscription and records what it is told
+type testHandler struct {
+	sub       Subscription
+	verified  []string
+	lease     time.Duration
+	delivered []string
+}
+
+func (th *testHandler) handler() *Handler {
+	return &Handler{
+		Lookup: func(ctx context.Context, id string) (Subscription, error) {
+			if id != th.sub.ID {
+				return Subscription{}, ErrUnknownSubscription
+			}
+			return th.sub, nil
+		},
+		Verified: func(ctx context.Context, sub Subscription, mode string, lease time.Duration) error {
+			th.verified = append(th.verified, mode)
+			th.lease = lease
+			return nil
+		},
+		Deliver: func(ctx context.Context, sub Subscription, contentType string, body []byte) error {
+			th.delivered = append(th.delivered, string(body))
+			return nil
+		},
+		MaxBodyBytes: 1024,
+	}
+}
+
+func TestHandlerVerify(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		query    url.Values
+		status   int
+		body     string
+		verified string
+	}{
+		{
+			name:     "subscribe",
+			id:       "abc",
+			query:    url.Values{"hub.mode": {"subscribe"}, "hub.topic": {"https://example.com/feed"}, "hub.challenge": {"xyz"}, "hub.lease_seconds": {"3600"}},
+			status:   http.StatusOK,
+			body:     "xyz",
+			verified: "subscribe",
+		},
+		{
+			name:   "wrong topic",
+			id:     "abc",
+			query:  url.Values{"hub.mode": {"subscribe"}, "hub.topic": {"https://evil.example/feed"}, "hub.challenge": {"xyz"}},
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "unknown subscription",
+			id:     "nope",
+			query:  url.Values{"hub.mode": {"subscribe"}, "hub.topic": {"https://example.com/feed"}, "hub.challenge": {"xyz"}},
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "unsubscribe from a forgotten subscription",
+			id:     "nope",
+			query:  url.Values{"hub.mode": {"unsubscribe"}, "hub.topic": {"https://example.com/feed"}, "hub.challenge": {"bye"}},
+			status: http.StatusOK,
+			body:   "bye",
+		},
+		{
+			name:   "unsubscribe from a wanted subscription",
+			id:     "abc",
+			query:  url.Values{"hub.mode": {"unsubscribe"}, "hub.topic": {"https://example.com/feed"}, "hub.challenge": {"bye"}},
+			status: http.StatusNotFound,
+		},
+		{
+			name:     "denied",
+			id:       "abc",
+			query:    url.Values{"hub.mode": {"denied"}, "hub.topic": {"https://example.com/feed"}, "hub.reason": {"no"}},
+			status:   http.StatusOK,
+			verified: "denied",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			th := &testHandler{sub: Subscription{ID: "abc", Topic: "https://example.com/feed", Secret: "s3cret"}}
+			req := httptest.NewRequest(http.MethodGet, "/websub/"+tt.id+"?"+tt.query.Encode(), nil)
+			rec := httptest.NewRecorder()
+			th.handler().ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if tt.body != "" && rec.Body.String() != tt.body {
+				t.Errorf("body = %q, want the challenge %q", rec.Body.String(), tt.body)
+			}
+			got := strings.Join(th.verified, ",")
+			if got != tt.verified {
+				t.Errorf("Verified called with %q, want %q", got, tt.verified)
+			}
+			if tt.verified == "subscribe" && th.lease != time.Hour {
+				t.Errorf("lease = %s, want 1h", th.lease)
+			}
+		})
+	}
+}
+
+func TestHandlerDeliver(t *testing.T) {
+	const body = `<feed xmlns="http://www.w3.org/2005/Atom"/>`
+	tests := []struct {
+		name      string
+		id        string
+		signature string
+		body      string
+		status    int
+		delivered bool
+	}{
+		{"signed", "abc", "sha256=" + sign(sha256.New, "s3cret", body), body, http.StatusAccepted, true},
+		{"bad signature is dropped", "abc", "sha256=" + sign(sha256.New, "other", body), body, http.StatusAccepted, false},
+		{"unsigned is dropped", "abc", "", body, http.StatusAccepted, false},
+		{"unknown subscription", "nope", "sha256=" + sign(sha256.New, "s3cret", body), body, http.StatusNotFound, false},
+		{"too large", "abc", "", strings.Repeat("x", 2048), http.StatusRequestEntityTooLarge, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			th := &testHandler{sub: Subscription{ID: "abc", Topic: "https://example.com/feed", Secret: "s3cret"}}
+			req := httptest.NewRequest(http.MethodPost, "/websub/"+tt.id, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/atom+xml")
+			if tt.signature != "" {
+				req.Header.Set("X-Hub-Signature", tt.signature)
+			}
+			rec := httptest.NewRecorder()
+			th.handler().ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if delivered := len(th.delivered) > 0; delivered != tt.delivered {
+				t.Errorf("delivered = %v, want %v", delivered, tt.delivered)
+			}
+		})
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	var form url.Values
+	hub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/x-www-form-urlencoded" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		r.ParseForm()
+		form = r.PostForm
+		if form.Get("hub.topic") == "https://example.com/refused" {
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, "topic not allowed")
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer hub.Close()
+	client, err := httpclient.New(httpclient.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = Subscribe(context.Background(), client, SubscribeRequest{
+		Hub:      hub.URL,
+		Topic:    "https://example.com/feed",
+		Callback: "https://gator.example/websub/abc",
+		Secret:   "s3cret",
+		Lease:    24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	want := url.Values{
+		"hub.mode":          {"subscribe"},
+		"hub.topic":         {"https://example.com/feed"},
+		"hub.callback":      {"https://gator.example/websub/abc"},
+		"hub.secret":        {"s3cret"},
+		"hub.lease_seconds": {"86400"},
+	}
+	if form.Encode() != want.Encode() {
+		t.Errorf("form = %s\nwant   %s", form.Encode(), want.Encode())
+	}
+
+	err = Unsubscribe(context.Background(), client, SubscribeRequest{
+		Hub:      hub.URL,
+		Topic:    "https://example.com/feed",
+		Callback: "https://gator.example/websub/abc",
+	})
+	if err != nil {
+		t.Fatalf("Unsubscribe: %v", err)
+	}
+	if form.Get("hub.mode") != "unsubscribe" || form.Has("hub.secret") {
+		t.Errorf("unsubscribe form = %s", form.Encode())
+	}
+
+	err = Subscribe(context.Background(), client, SubscribeRequest{Hub: hub.URL, Topic: "https://example.com/refused"})
+	if err == nil || !strings.Contains(err.Error(), "topic not allowed") {
+		t.Errorf("Subscribe to a refusing hub = %v, want the hub's reason", err)
+	}
+}
@@ -1,21 +1,31 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"database/sql"
-	"encoding/xml"
+	"errors"
+	"flag"
 	"fmt"
-	"html"
-	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Uttam1916/Gator/internal/config"
+	"github.com/Uttam1916/Gator/internal/credentials"
 	"github.com/Uttam1916/Gator/internal/database"
+	"github.com/Uttam1916/Gator/internal/download"
+	"github.com/Uttam1916/Gator/internal/feedparse"
+	"github.com/Uttam1916/Gator/internal/httpclient"
+	"github.com/Uttam1916/Gator/internal/localfeed"
+	"github.com/Uttam1916/Gator/internal/readability"
+	"github.com/Uttam1916/Gator/internal/render"
+	"github.com/Uttam1916/Gator/internal/websub"
 	"github.com/google/uuid"
 	_ "github.com/lib/pq"
 )
@@ -23,6 +33,8 @@ import (
 type state struct {
 	configpointer *config.Config
 	db            *database.Queries
+	client        *httpclient.Client
+	schedule      schedulePolicy
 }
 
 type command struct {
@@ -34,22 +46,6 @@ type commands struct {
 	command_map map[string]func(*state, command) error
 }
 
-type RSSFeed struct {
-	Channel struct {
-		Title       string    `xml:"title"`
-		Link        string    `xml:"link"`
-		Description string    `xml:"description"`
-		Items       []RSSItem `xml:"item"`
-	} `xml:"channel"`
-}
-
-type RSSItem struct {
-	Title       string `xml:"title"`
-	Link        string `xml:"link"`
-	Description string `xml:"description"`
-	PubDate     string `xml:"pubDate"`
-}
-
 // declare config variables
 var ste state
 var cfg config.Config
@@ -65,10 +61,20 @@ func main() {
 	dbQueries := database.New(conn)
 
 	cfg = config.Read()
+	client, err := httpclient.FromConfig(cfg)
+	if err != nil {
+		log.Fatal("could not set up http client:", err)
+	}
+	schedule, err := schedulePolicyFromConfig(cfg)
+	if err != nil {
+		log.Fatal("invalid fetch schedule:", err)
+	}
 
 	ste = state{
 		db:            dbQueries,
 		configpointer: &cfg,
+		client:        client,
+		schedule:      schedule,
 	}
 
 	if len(os.Args) < 2 {
@@ -89,10 +95,15 @@ func main() {
 	comms.register("agg", handlerAgg)
 	comms.register("addfeed", middlewareLogin(handlerAddFeed))
 	comms.register("feeds", handlerFeeds)
+	comms.register("feed", handlerFeed)
 	comms.register("follow", middlewareLogin(handlerFollow))
 	comms.register("following", middlewareLogin(handlerFollowing))
 	comms.register("unfollow", middlewareLogin(handlerUnfollow))
 	comms.register("browse", middlewareLogin(handlerBrowse))
+	comms.register("read", handlerRead)
+	comms.register("download", middlewareLogin(handlerDownload))
+	comms.register("serve", handlerServe)
+	comms.register("validate", handlerValidate)
 	comms.register("help", handlerHelp)
 
 	err = comms.run(&ste, cmd)
@@ -181,40 +192,243 @@ func handlerUsers(s *state, c command) error {
 	return nil
 }
 
-func fetchFeed(ctx context.Context, feedURL string) (*RSSFeed, error) {
+// validators remembered from the previous fetch of a feed
+type cacheHeaders struct {
+	etag         string
+	lastModified string
+}
+
+// fetchResult is what a successful fetch hands back to scrapeFeeds, feed is
+// nil when the server answered 304 Not Modified
+type fetchResult struct {
+	feed   *feedparse.Feed
+	cache  cacheHeaders
+	status int
+	// movedTo is set when every redirect on the way was permanent
+	movedTo string
+}
+
+// statusError is returned when a feed answers with an unusable status
+type statusError struct {
+	status     int
+	retryAfter time.Duration
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status: %d %s", e.status, http.StatusText(e.status))
+}
+
+func fetchFeed(ctx context.Context, client *httpclient.Client, feedURL string, cache cacheHeaders, auth credentials.Credentials) (fetchResult, error) {
+	result := fetchResult{cache: cache}
 	// create the request
-	req, err := http.NewRequestWithContext(ctx, "GET", feedURL, nil)
+	req, err := client.NewRequest(ctx, feedURL)
 	if err != nil {
-		return nil, fmt.Errorf("couldnt form request\n")
+		return result, fmt.Errorf("couldnt form request: %v", err)
 	}
-	req.Header.Set("User-Agent", "gator")
-	// use client to make the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	auth.Apply(req)
+	// only ask for the body if it changed since the last fetch
+	if cache.etag != "" {
+		req.Header.Set("If-None-Match", cache.etag)
+	}
+	if cache.lastModified != "" {
+		req.Header.Set("If-Modified-Since", cache.lastModified)
+	}
+	// use a copy of the shared client to watch the redirects it follows,
+	// the transport and its connections are still shared
+	permanent := true
+	watched := *client.HTTP
+	watched.CheckRedirect = authRedirectPolicy(auth, func(next *http.Request) {
+		if permanent && isPermanentRedirect(next.Response.StatusCode) {
+			result.movedTo = next.URL.String()
+		} else {
+			// a temporary hop anywhere in the chain keeps the old url
+			permanent = false
+			result.movedTo = ""
+		}
+	})
+	resp, err := watched.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("error recieving response\n")
+		return result, fmt.Errorf("error recieving response: %v", err)
 	}
 	defer resp.Body.Close()
-	// obtain and convert xml into a struct
-	body, err := io.ReadAll(resp.Body)
+	result.status = resp.StatusCode
+	if resp.StatusCode == http.StatusNotModified {
+		return result, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		statusErr := &statusError{status: resp.StatusCode}
+		// servers that are throttling us say when to come back
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+			statusErr.retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		}
+		return result, statusErr
+	}
+	result.cache = cacheHeaders{
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+	}
+	// read the raw feed body
+	body, err := client.ReadBody(resp)
 	if err != nil {
-		return nil, fmt.Errorf("error reading body\n")
+		return result, fmt.Errorf("error reading body: %v", err)
 	}
-	var RSSresp RSSFeed
+	// let the feed parser sniff out the format
+	result.feed, err = feedparse.Parse(resp.Header.Get("Content-Type"), body)
+	if err != nil {
+		return result, fmt.Errorf("couldnt parse feed: %v", err)
+	}
+	return result, nil
+}
 
-	err = xml.Unmarshal(body, &RSSresp)
+// authRedirectPolicy follows up to 10 redirects and removes custom auth
+// headers once a redirect leaves the original host, net/http only does that
+// for authorization and cookies. watch, when set, sees every redirect.
+func authRedirectPolicy(auth credentials.Credentials, watch func(next *http.Request)) func(*http.Request, []*http.Request) error {
+	return func(next *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		if next.URL.Host != via[0].URL.Host {
+			for name := range auth.Headers {
+				next.Header.Del(name)
+			}
+		}
+		if watch != nil {
+			watch(next)
+		}
+		return nil
+	}
+}
+
+// fetchLocalFeed reads a file:// or exec: feed. Files report their
+// modification time so unchanged ones are skipped like a 304.
+func fetchLocalFeed(ctx context.Context, s *state, feedURL string, cache cacheHeaders) (fetchResult, error) {
+	result := fetchResult{cache: cache}
+	source := localfeed.Source{
+		Commands:     s.configpointer.Exec_commands,
+		MaxBodyBytes: s.client.MaxBodyBytes,
+		Timeout:      s.client.HTTP.Timeout,
+	}
+	local, err := source.Read(ctx, feedURL)
+	if err != nil {
+		return result, fmt.Errorf("couldnt read local feed: %v", err)
+	}
+	if !local.ModTime.IsZero() {
+		modified := local.ModTime.UTC().Format(http.TimeFormat)
+		if modified == cache.lastModified {
+			result.status = http.StatusNotModified
+			return result, nil
+		}
+		result.cache = cacheHeaders{lastModified: modified}
+	}
+	result.status = http.StatusOK
+	result.feed, err = feedparse.Parse(local.ContentType, local.Body)
 	if err != nil {
-		return nil, fmt.Errorf("couldnt convert xml into go struct\n")
+		return result, fmt.Errorf("couldnt parse feed: %v", err)
+	}
+	return result, nil
+}
+
+func isPermanentRedirect(status int) bool {
+	return status == http.StatusMovedPermanently || status == http.StatusPermanentRedirect
+}
+
+// parseRetryAfter reads a Retry-After header given either in seconds or as
+// an http date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if when, err := http.ParseTime(value); err == nil && when.After(now) {
+		return when.Sub(now)
 	}
-	//clean up the struct feilds
-	RSSresp.Channel.Title = html.UnescapeString(RSSresp.Channel.Title)
-	RSSresp.Channel.Description = html.UnescapeString(RSSresp.Channel.Description)
-	for i := range RSSresp.Channel.Items {
-		RSSresp.Channel.Items[i].Title = html.UnescapeString(RSSresp.Channel.Items[i].Title)
-		RSSresp.Channel.Items[i].Description = html.UnescapeString(RSSresp.Channel.Items[i].Description)
+	return 0
+}
+
+// backoff bounds for feeds that keep failing
+const (
+	minFailureBackoff = time.Minute
+	maxFailureBackoff = 24 * time.Hour
+)
+
+// failureBackoff doubles the wait for every consecutive failure
+func failureBackoff(failures int32) time.Duration {
+	delay := minFailureBackoff
+	for i := int32(1); i < failures && delay < maxFailureBackoff; i++ {
+		delay *= 2
 	}
+	return min(delay, maxFailureBackoff)
+}
 
-	return &RSSresp, nil
+// schedulePolicy bounds the polling interval publishers ask for
+type schedulePolicy struct {
+	minInterval time.Duration
+	maxInterval time.Duration
+	// maxFailures is how many failed fetches in a row disable a feed
+	maxFailures int32
+}
+
+// feeds without hints are polled on every agg tick unless a minimum is set
+const (
+	defaultMaxFetchInterval = 24 * time.Hour
+	defaultMaxFailures      = 10
+)
+
+func schedulePolicyFromConfig(c config.Config) (schedulePolicy, error) {
+	policy := schedulePolicy{maxInterval: defaultMaxFetchInterval, maxFailures: defaultMaxFailures}
+	if c.Max_consecutive_failures > 0 {
+		policy.maxFailures = c.Max_consecutive_failures
+	}
+	if c.Min_fetch_interval != "" {
+		interval, err := time.ParseDuration(c.Min_fetch_interval)
+		if err != nil {
+			return policy, fmt.Errorf("invalid min_fetch_interval: %v", err)
+		}
+		policy.minInterval = interval
+	}
+	if c.Max_fetch_interval != "" {
+		interval, err := time.ParseDuration(c.Max_fetch_interval)
+		if err != nil {
+			return policy, fmt.Errorf("invalid max_fetch_interval: %v", err)
+		}
+		policy.maxInterval = interval
+	}
+	if policy.maxInterval < policy.minInterval {
+		return policy, fmt.Errorf("max_fetch_interval is shorter than min_fetch_interval")
+	}
+	return policy, nil
+}
+
+// nextFetchTime picks when to poll a feed again from its hints, moving past
+// the hours and days the publisher asked us to skip
+func (p schedulePolicy) nextFetchTime(now time.Time, schedule feedparse.Schedule) time.Time {
+	delay := min(max(schedule.Interval, p.minInterval), p.maxInterval)
+	next := now.Add(delay)
+	for schedule.Skips(next) {
+		next = next.UTC().Truncate(time.Hour).Add(time.Hour)
+		if next.Sub(now) >= p.maxInterval {
+			return now.Add(p.maxInterval)
+		}
+	}
+	return next
+}
+
+// storedSchedule rebuilds the hints saved from the last full fetch
+func storedSchedule(feed database.Feed) feedparse.Schedule {
+	schedule := feedparse.Schedule{
+		Interval: time.Duration(feed.UpdateIntervalSeconds.Int32) * time.Second,
+	}
+	for _, hour := range feed.SkipHours {
+		schedule.SkipHours = append(schedule.SkipHours, int(hour))
+	}
+	for _, day := range feed.SkipDays {
+		schedule.SkipDays = append(schedule.SkipDays, time.Weekday(day))
+	}
+	return schedule
 }
 
 func handlerAgg(s *state, c command) error {
@@ -233,18 +447,24 @@ func handlerAgg(s *state, c command) error {
 	defer ticker.Stop()
 
 	// Run once immediately
-	scrapeFeeds(s)
+	renewWebsubLeases(s)
+	if err := scrapeFeeds(s); err != nil {
+		fmt.Printf("error:%v \n", err)
+	}
 
 	// Then on each tick
 	for range ticker.C {
-		scrapeFeeds(s)
+		renewWebsubLeases(s)
+		if err := scrapeFeeds(s); err != nil {
+			fmt.Printf("error:%v \n", err)
+		}
 	}
 
-	rss, err := fetchFeed(context.Background(), "https://www.wagslane.dev/index.xml")
+	rss, err := fetchFeed(context.Background(), s.client, "https://www.wagslane.dev/index.xml", cacheHeaders{}, credentials.Credentials{})
 	if err != nil {
 		return fmt.Errorf("error reading go struct\n")
 	}
-	fmt.Println(rss)
+	fmt.Println(rss.feed)
 	return nil
 }
 
@@ -262,21 +482,105 @@ func middlewareLogin(handler func(s *state, c command, user database.User) error
 	}
 }
 
+// parseFlags parses a command's flags. flag stops at the first positional
+// argument, so flags given after one are rejected instead of being silently
+// taken as arguments.
+func parseFlags(flags *flag.FlagSet, arguments []string) error {
+	if err := flags.Parse(arguments); err != nil {
+		return err
+	}
+	for _, arg := range flags.Args() {
+		if strings.HasPrefix(arg, "-") && arg != "-" {
+			return fmt.Errorf("flags go before the other arguments, move %s to the front", arg)
+		}
+	}
+	return nil
+}
+
+// authFlags collects repeated --auth values
+type authFlags []string
+
+func (a *authFlags) String() string {
+	return strings.Join(*a, ",")
+}
+
+func (a *authFlags) Set(value string) error {
+	*a = append(*a, value)
+	return nil
+}
+
+// sealAuth parses --auth values and encrypts them for storage, nil means the
+// feed needs no credentials
+func sealAuth(s *state, specs []string) ([]byte, credentials.Credentials, error) {
+	creds, err := credentials.Parse(specs)
+	if err != nil || creds.IsZero() {
+		return nil, creds, err
+	}
+	key, err := credentials.ParseKey(s.configpointer.Credentials_key)
+	if err != nil {
+		return nil, creds, err
+	}
+	sealed, err := credentials.Seal(key, creds)
+	if err != nil {
+		return nil, creds, fmt.Errorf("couldnt encrypt credentials: %v", err)
+	}
+	return sealed, creds, nil
+}
+
+// feedCredentials decrypts the credentials stored for a feed
+func feedCredentials(s *state, feed database.Feed) (credentials.Credentials, error) {
+	if len(feed.Credentials) == 0 {
+		return credentials.Credentials{}, nil
+	}
+	key, err := credentials.ParseKey(s.configpointer.Credentials_key)
+	if err != nil {
+		return credentials.Credentials{}, err
+	}
+	return credentials.Open(key, feed.Credentials)
+}
+
 func handlerAddFeed(s *state, c command, user database.User) error {
-	if len(c.arguments) < 2 {
+	flags := flag.NewFlagSet("addfeed", flag.ContinueOnError)
+	var auth authFlags
+	flags.Var(&auth, "auth", "credentials for a private feed, may be repeated")
+	if err := parseFlags(flags, c.arguments); err != nil {
+		return err
+	}
+	if flags.NArg() < 2 {
 		return fmt.Errorf("this function requires url and name")
 	}
+	sealed, creds, err := sealAuth(s, auth)
+	if err != nil {
+		return err
+	}
 	// create feed struct and get userID being tied to feed
 	userid, err := s.db.GetUserIdByName(context.Background(), s.configpointer.Current_username)
 	if err != nil {
 		return fmt.Errorf("error obtaining user id\n")
 	}
+	// users often paste a homepage, look for the feed it advertises
+	feedURL := flags.Arg(1)
+	if localfeed.IsLocal(feedURL) {
+		// make sure the file or command produces a feed before adding it
+		if _, err := fetchLocalFeed(context.Background(), s, feedURL, cacheHeaders{}); err != nil {
+			return err
+		}
+	} else {
+		feedURL, err = discoverFeedURL(context.Background(), s.client, flags.Arg(1), creds)
+		if err != nil {
+			return err
+		}
+		// the credentials were given for the page, not for wherever it links
+		if !creds.IsZero() && !sameHost(flags.Arg(1), feedURL) {
+			return fmt.Errorf("%s is on a different host than %s, refusing to send its credentials there. Add the feed url directly if it needs them", feedURL, flags.Arg(1))
+		}
+	}
 	feedinfo := database.CreateFeedParams{
 		ID:        uuid.New(),
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
-		Name:      c.arguments[0],
-		Url:       c.arguments[1],
+		Name:      flags.Arg(0),
+		Url:       feedURL,
 		UserID:    userid,
 	}
 
@@ -291,6 +595,16 @@ func handlerAddFeed(s *state, c command, user database.User) error {
 	fmt.Printf("  User ID   : %s\n", feed.UserID)
 	fmt.Printf("  Created At: %s\n", feed.CreatedAt.Format(time.RFC3339))
 	fmt.Printf("  Updated At: %s\n", feed.UpdatedAt.Format(time.RFC3339))
+	if sealed != nil {
+		_, err = s.db.SetFeedCredentials(context.Background(), database.SetFeedCredentialsParams{
+			Url:         feed.Url,
+			Credentials: sealed,
+		})
+		if err != nil {
+			return fmt.Errorf("feed created but storing credentials failed: %v", err)
+		}
+		fmt.Printf("  Auth      : %s\n", creds.Describe())
+	}
 	// Automatically follow the feed
 	feedfollowparams := database.CreateFeedFollowParams{
 		ID:        uuid.New(),
@@ -310,7 +624,73 @@ func handlerAddFeed(s *state, c command, user database.User) error {
 	return nil
 }
 
+// discoverFeedURL returns the feed to subscribe to for a url, which is the url
+// itself unless it points to an html page advertising feeds
+func discoverFeedURL(ctx context.Context, client *httpclient.Client, pageURL string, auth credentials.Credentials) (string, error) {
+	req, err := client.NewRequest(ctx, pageURL)
+	if err != nil {
+		return "", fmt.Errorf("couldnt form request: %v", err)
+	}
+	auth.Apply(req)
+	checked := *client.HTTP
+	checked.CheckRedirect = authRedirectPolicy(auth, nil)
+	resp, err := checked.Do(req)
+	if err != nil {
+		// the feed may just be down right now, keep what the user gave us
+		fmt.Printf("Could not check %s, adding it as is: %v\n", pageURL, err)
+		return pageURL, nil
+	}
+	defer resp.Body.Close()
+	body, err := client.ReadBody(resp)
+	if err != nil {
+		return "", fmt.Errorf("error reading body: %v", err)
+	}
+	if !feedparse.IsHTML(resp.Header.Get("Content-Type"), body) {
+		return pageURL, nil
+	}
+	// resolve links against where we ended up after redirects
+	candidates, err := feedparse.Discover(resp.Request.URL.String(), body)
+	if err != nil {
+		return "", fmt.Errorf("couldnt parse html page: %v", err)
+	}
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("%s is a web page that does not advertise any feeds", pageURL)
+	case 1:
+		fmt.Printf("Found feed %s\n", candidates[0].URL)
+		return candidates[0].URL, nil
+	}
+	fmt.Printf("%s advertises %d feeds:\n", pageURL, len(candidates))
+	for i, candidate := range candidates {
+		title := candidate.Title
+		if title == "" {
+			title = "(untitled)"
+		}
+		fmt.Printf(" %d. %s [%s]\n    %s\n", i+1, title, candidate.Type, candidate.URL)
+	}
+	fmt.Printf("Choose a feed [1-%d] (default 1): ", len(candidates))
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	line = strings.TrimSpace(line)
+	if line == "" {
+		// no answer or no terminal, go with the page's first choice
+		return candidates[0].URL, nil
+	}
+	choice, err := strconv.Atoi(line)
+	if err != nil || choice < 1 || choice > len(candidates) {
+		return "", fmt.Errorf("invalid choice: %s", line)
+	}
+	return candidates[choice-1].URL, nil
+}
+
 func handlerFeeds(s *state, c command) error {
+	flags := flag.NewFlagSet("feeds", flag.ContinueOnError)
+	health := flags.Bool("health", false, "show how well each feed is being fetched")
+	if err := parseFlags(flags, c.arguments); err != nil {
+		return err
+	}
+	if *health {
+		return printFeedHealth(s)
+	}
 	feeds, err := s.db.ReturnAllFeedsWithUsers(context.Background())
 	if err != nil {
 		return fmt.Errorf("couldnt retrieve feed data from database\n")
@@ -319,11 +699,217 @@ func handlerFeeds(s *state, c command) error {
 		fmt.Println("----------")
 		fmt.Printf("Feed Name : %s\n", feed.Name)
 		fmt.Printf("Feed URL  : %s\n", feed.Url)
+		printOptional("Title     : %s\n", feed.ChannelTitle)
+		printOptional("Site      : %s\n", feed.SiteLink)
+		printOptional("Language  : %s\n", feed.Language)
+		if feed.HasCredentials {
+			fmt.Println("Auth      : yes")
+		}
+		if feed.DisabledAt.Valid {
+			fmt.Printf("Disabled  : since %s\n", feed.DisabledAt.Time.Format(time.RFC1123))
+		}
 		fmt.Printf("Created By: %s\n", feed.Username)
 	}
 	return nil
 }
 
+// printFeedHealth lists fetch statistics, broken and disabled feeds first
+func printFeedHealth(s *state) error {
+	feeds, err := s.db.GetFeedHealth(context.Background())
+	if err != nil {
+		return fmt.Errorf("couldnt retrieve feed health from database: %v", err)
+	}
+	for _, feed := range feeds {
+		status := "✅ ok"
+		switch {
+		case feed.DisabledAt.Valid:
+			status = "⛔ disabled since " + feed.DisabledAt.Time.Format(time.RFC1123)
+		case feed.ConsecutiveFailures > 0:
+			status = "⚠️  failing"
+		case !feed.LastFetchedAt.Valid:
+			status = "⏳ never fetched"
+		}
+		fmt.Println("----------")
+		fmt.Printf("Feed Name    : %s\n", feed.Name)
+		fmt.Printf("Feed URL     : %s\n", feed.Url)
+		fmt.Printf("Status       : %s\n", status)
+		fmt.Printf("Last Fetch   : %s\n", formatOptionalTime(feed.LastFetchedAt))
+		if feed.LastStatus.Valid {
+			fmt.Printf("Last Status  : %d %s\n", feed.LastStatus.Int32, http.StatusText(int(feed.LastStatus.Int32)))
+		}
+		printOptional("Last Error   : %s\n", feed.LastError)
+		fmt.Printf("Failures     : %d in a row\n", feed.ConsecutiveFailures)
+		if feed.FetchCount > 0 {
+			fmt.Printf("Items        : %.1f per fetch over %d fetches\n", float64(feed.ItemCount)/float64(feed.FetchCount), feed.FetchCount)
+		}
+		fmt.Printf("New Posts    : %d\n", feed.NewPostCount)
+		fmt.Printf("Last New Post: %s\n", formatOptionalTime(feed.LastPostAt))
+		if !feed.DisabledAt.Valid && feed.NextFetchAt.Valid {
+			fmt.Printf("Next Fetch   : %s\n", feed.NextFetchAt.Time.Format(time.RFC1123))
+		}
+	}
+	return nil
+}
+
+func formatOptionalTime(t sql.NullTime) string {
+	if !t.Valid {
+		return "never"
+	}
+	return t.Time.Format(time.RFC1123)
+}
+
+// handlerFeed groups the settings that apply to a single feed
+func handlerFeed(s *state, c command) error {
+	if len(c.arguments) < 1 {
+		return fmt.Errorf("this function requires a subcommand")
+	}
+	sub := command{name: c.arguments[0], arguments: c.arguments[1:]}
+	switch sub.name {
+	case "fulltext":
+		return handlerFeedFullText(s, sub)
+	case "info":
+		return handlerFeedInfo(s, sub)
+	case "set-auth":
+		return handlerFeedSetAuth(s, sub)
+	case "enable":
+		return handlerFeedEnable(s, sub)
+	default:
+		return fmt.Errorf("unknown feed subcommand: %s", sub.name)
+	}
+}
+
+func handlerFeedInfo(s *state, c command) error {
+	if len(c.arguments) < 1 {
+		return fmt.Errorf("this function requires url")
+	}
+	feed, err := s.db.GetFeedByUrl(context.Background(), c.arguments[0])
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("no feed with url %s", c.arguments[0])
+	}
+	if err != nil {
+		return fmt.Errorf("error obtaining feed: %v", err)
+	}
+	fmt.Printf("Feed Name   : %s\n", feed.Name)
+	fmt.Printf("Feed URL    : %s\n", feed.Url)
+	printOptional("Title       : %s\n", feed.ChannelTitle)
+	if feed.ChannelDescription.Valid {
+		fmt.Printf("Description : %s\n", render.Text(feed.ChannelDescription.String, render.TerminalWidth()))
+	}
+	printOptional("Site        : %s\n", feed.SiteLink)
+	printOptional("Language    : %s\n", feed.Language)
+	printOptional("Image       : %s\n", feed.ImageUrl)
+	printOptional("Generator   : %s\n", feed.Generator)
+	if feed.LastBuildDate.Valid {
+		fmt.Printf("Last Build  : %s\n", feed.LastBuildDate.Time.Format(time.RFC1123))
+	}
+	if feed.LastFetchedAt.Valid {
+		fmt.Printf("Last Fetch  : %s\n", feed.LastFetchedAt.Time.Format(time.RFC1123))
+	}
+	if feed.NextFetchAt.Valid {
+		fmt.Printf("Next Fetch  : %s\n", feed.NextFetchAt.Time.Format(time.RFC1123))
+	}
+	if feed.UpdateIntervalSeconds.Valid {
+		fmt.Printf("Updates     : every %s\n", time.Duration(feed.UpdateIntervalSeconds.Int32)*time.Second)
+	}
+	fmt.Printf("Full Text   : %t\n", feed.FetchFullText)
+	if feed.DisabledAt.Valid {
+		fmt.Printf("Disabled    : since %s after %d failures\n", feed.DisabledAt.Time.Format(time.RFC1123), feed.ConsecutiveFailures)
+	}
+	if len(feed.Credentials) > 0 {
+		// only say what kind of credentials are stored, never their values
+		if creds, err := feedCredentials(s, feed); err == nil {
+			fmt.Printf("Auth        : %s\n", creds.Describe())
+		} else {
+			fmt.Println("Auth        : stored, encrypted")
+		}
+	}
+	return nil
+}
+
+// handlerFeedEnable puts a disabled feed back into the fetch rotation
+func handlerFeedEnable(s *state, c command) error {
+	if len(c.arguments) < 1 {
+		return fmt.Errorf("this function requires url")
+	}
+	updated, err := s.db.EnableFeed(context.Background(), c.arguments[0])
+	if err != nil {
+		return fmt.Errorf("error updating feed: %v", err)
+	}
+	if updated == 0 {
+		return fmt.Errorf("no feed with url %s", c.arguments[0])
+	}
+	fmt.Printf("%s is enabled and will be fetched on the next agg tick\n", c.arguments[0])
+	return nil
+}
+
+// handlerFeedSetAuth replaces a feed's credentials, without --auth they are
+// removed
+func handlerFeedSetAuth(s *state, c command) error {
+	flags := flag.NewFlagSet("feed set-auth", flag.ContinueOnError)
+	var auth authFlags
+	flags.Var(&auth, "auth", "credentials for a private feed, may be repeated")
+	if err := parseFlags(flags, c.arguments); err != nil {
+		return err
+	}
+	if flags.NArg() < 1 {
+		return fmt.Errorf("this function requires url")
+	}
+	sealed, creds, err := sealAuth(s, auth)
+	if err != nil {
+		return err
+	}
+	updated, err := s.db.SetFeedCredentials(context.Background(), database.SetFeedCredentialsParams{
+		Url:         flags.Arg(0),
+		Credentials: sealed,
+	})
+	if err != nil {
+		return fmt.Errorf("error updating feed: %v", err)
+	}
+	if updated == 0 {
+		return fmt.Errorf("no feed with url %s", flags.Arg(0))
+	}
+	if sealed == nil {
+		fmt.Printf("Removed credentials for %s\n", flags.Arg(0))
+		return nil
+	}
+	fmt.Printf("Stored %s for %s\n", creds.Describe(), flags.Arg(0))
+	return nil
+}
+
+// printOptional prints a line only when the column has a value
+func printOptional(format string, value sql.NullString) {
+	if value.Valid {
+		fmt.Printf(format, value.String)
+	}
+}
+
+func handlerFeedFullText(s *state, c command) error {
+	if len(c.arguments) < 2 {
+		return fmt.Errorf("this function requires url and on/off")
+	}
+	var enabled bool
+	switch c.arguments[1] {
+	case "on":
+		enabled = true
+	case "off":
+		enabled = false
+	default:
+		return fmt.Errorf("expected on or off, got %s", c.arguments[1])
+	}
+	updated, err := s.db.SetFeedFetchFullText(context.Background(), database.SetFeedFetchFullTextParams{
+		Url:           c.arguments[0],
+		FetchFullText: enabled,
+	})
+	if err != nil {
+		return fmt.Errorf("error updating feed: %v", err)
+	}
+	if updated == 0 {
+		return fmt.Errorf("no feed with url %s", c.arguments[0])
+	}
+	fmt.Printf("Full text fetching for %s is now %s\n", c.arguments[0], c.arguments[1])
+	return nil
+}
+
 func handlerFollow(s *state, c command, user database.User) error {
 	userid, err := s.db.GetUserIdByName(context.Background(), s.configpointer.Current_username)
 	if err != nil {
@@ -381,6 +967,10 @@ func handlerUnfollow(s *state, c command, user database.User) error {
 
 func scrapeFeeds(s *state) error {
 	nextfeed, err := s.db.GetNextFeed(context.Background())
+	if errors.Is(err, sql.ErrNoRows) {
+		fmt.Println("⏳ No feeds are due for fetching")
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("error getting next feed\n")
 	}
@@ -390,89 +980,943 @@ func scrapeFeeds(s *state) error {
 	if err != nil {
 		return fmt.Errorf("error marking fetched feed\n")
 	}
-	feed, err := fetchFeed(context.Background(), nextfeed.Url)
+	cache := cacheHeaders{
+		etag:         nextfeed.Etag.String,
+		lastModified: nextfeed.LastModified.String,
+	}
+	auth, err := feedCredentials(s, nextfeed)
+	if err != nil {
+		recordFetchFailure(s, nextfeed, 0, err)
+		return fmt.Errorf("error reading credentials for '%s': %v", nextfeed.Name, err)
+	}
+	var result fetchResult
+	if localfeed.IsLocal(nextfeed.Url) {
+		result, err = fetchLocalFeed(context.Background(), s, nextfeed.Url, cache)
+	} else {
+		result, err = fetchFeed(context.Background(), s.client, nextfeed.Url, cache, auth)
+	}
 	if err != nil {
-		return fmt.Errorf("error getting feed from database\n")
+		recordFetchFailure(s, nextfeed, result.status, err)
+		return fmt.Errorf("error fetching feed '%s': %v", nextfeed.Name, err)
 	}
+	// a 304 has no body, keep using the hints from the last full fetch
+	schedule := storedSchedule(nextfeed)
+	if result.feed != nil {
+		schedule = result.feed.Schedule
+	}
+	// pushed updates arrive through serve, polling is only a fallback
+	if websubActive(s, nextfeed.ID) {
+		schedule.Interval = s.schedule.maxInterval
+	}
+	nextFetch := s.schedule.nextFetchTime(time.Now(), schedule)
+	err = s.db.MarkFeedFetchSucceeded(context.Background(), database.MarkFeedFetchSucceededParams{
+		ID:          nextfeed.ID,
+		LastStatus:  sql.NullInt32{Int32: int32(result.status), Valid: true},
+		NextFetchAt: sql.NullTime{Time: nextFetch, Valid: true},
+	})
+	if err != nil {
+		fmt.Printf("Failed to record fetch status: %v\n", err)
+	}
+	if wait := time.Until(nextFetch).Round(time.Second); wait > 0 {
+		fmt.Printf("🕒 Next fetch of '%s' in %s\n", nextfeed.Name, wait)
+	}
+	if result.movedTo != "" && result.movedTo != nextfeed.Url {
+		recordFeedMove(s, nextfeed, result.movedTo)
+	}
+	if result.feed == nil {
+		fmt.Printf("✅ '%s' has not changed since the last fetch\n", nextfeed.Name)
+		return nil
+	}
+	feed := result.feed
+	// remember the validators for the next conditional request
+	err = s.db.UpdateFeedCacheHeaders(context.Background(), database.UpdateFeedCacheHeadersParams{
+		ID:           nextfeed.ID,
+		Etag:         sql.NullString{String: result.cache.etag, Valid: result.cache.etag != ""},
+		LastModified: sql.NullString{String: result.cache.lastModified, Valid: result.cache.lastModified != ""},
+	})
+	if err != nil {
+		fmt.Printf("Failed to store cache headers: %v\n", err)
+	}
+	storeFeedMetadata(s, nextfeed.ID, feed)
+	storeFeedSchedule(s, nextfeed.ID, feed.Schedule)
+
+	syncWebsubSubscription(s, nextfeed, feed)
 
-	numPosts := len(feed.Channel.Items)
+	numPosts := len(feed.Items)
 	fmt.Printf("📰 Found %d posts in '%s'\n", numPosts, nextfeed.Name)
+	// unchanged feeds return before this, so they do not drag the average down
+	err = s.db.AddFeedFetchedItems(context.Background(), database.AddFeedFetchedItemsParams{
+		ID:        nextfeed.ID,
+		ItemCount: int32(numPosts),
+	})
+	if err != nil {
+		fmt.Printf("Failed to count fetched items: %v\n", err)
+	}
+	storePosts(s, nextfeed, feed.Items)
+	return nil
+}
 
-	for _, item := range feed.Channel.Items {
-		// Parse publication date
+// storePosts saves the items not seen before for a feed, polled and pushed
+// content both go through here. It returns how many posts were new and adds
+// them to the feed's health counters.
+func storePosts(s *state, dbFeed database.Feed, items []feedparse.Item) int {
+	created := 0
+	fetchedAt := time.Now()
+	for _, item := range items {
+		// Parse publication date, falling back to when we saw the post
+		estimated := false
 		publishedAt, err := parsePubDate(item.PubDate)
 		if err != nil {
-			fmt.Printf("Failed to parse pub date: %v\n", err)
-			continue
+			fmt.Printf("Using fetch time for '%s': %v\n", item.Title, err)
+			publishedAt = fetchedAt
+			estimated = true
 		}
 
 		// Create post record
 		post := database.CreatePostParams{
-			ID:          uuid.New(),
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
-			Title:       item.Title,
-			Url:         item.Link,
-			Description: item.Description,
-			PublishedAt: sql.NullTime{Time: publishedAt, Valid: true},
-			FeedID:      nextfeed.ID,
+			ID:                   uuid.New(),
+			CreatedAt:            time.Now(),
+			UpdatedAt:            time.Now(),
+			Title:                item.Title,
+			Url:                  item.Link,
+			Description:          item.Description,
+			PublishedAt:          sql.NullTime{Time: publishedAt, Valid: true},
+			FeedID:               dbFeed.ID,
+			Content:              sql.NullString{String: item.Content, Valid: item.Content != ""},
+			Guid:                 item.Key(),
+			PublishedAtEstimated: estimated,
 		}
 
-		err = s.db.CreatePost(context.Background(), post)
+		inserted, err := s.db.CreatePost(context.Background(), post)
 		if err != nil {
-			if strings.Contains(err.Error(), "duplicate key") {
-				// Skip duplicates silently
-				continue
-			}
 			fmt.Printf("Failed to insert post: %v\n", err)
+			continue
+		}
+		if inserted == 0 {
+			// Already stored for this feed
+			continue
+		}
+		created++
+		storeAttachments(s, post.ID, item.Enclosures)
+		storeAuthorsAndCategories(s, post.ID, item)
+		if dbFeed.FetchFullText && item.Link != "" {
+			storeFullText(s, post.ID, item.Link)
+		}
+	}
+	if created > 0 {
+		err := s.db.AddFeedNewPosts(context.Background(), database.AddFeedNewPostsParams{
+			ID:           dbFeed.ID,
+			NewPostCount: int32(created),
+		})
+		if err != nil {
+			fmt.Printf("Failed to count new posts: %v\n", err)
 		}
 	}
+	return created
+}
+
+// websub timings, leases are renewed a day before they run out and requests a
+// hub has not confirmed yet are not repeated for a while
+const (
+	websubLease       = 10 * 24 * time.Hour
+	websubRenewBefore = 24 * time.Hour
+	websubRetryAfter  = 10 * time.Minute
+	defaultServeAddr  = ":8080"
+)
+
+// syncWebsubSubscription subscribes to the hub a feed announces, following
+// it when the hub or topic changes and forgetting it when the hub goes away
+func syncWebsubSubscription(s *state, dbFeed database.Feed, feed *feedparse.Feed) {
+	if s.configpointer.Websub_callback_url == "" {
+		return
+	}
+	existing, err := s.db.GetWebsubSubscriptionForFeed(context.Background(), dbFeed.ID)
+	found := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		fmt.Printf("Failed to look up websub subscription: %v\n", err)
+		return
+	}
+	if feed.Hub == "" {
+		if found {
+			dropWebsubSubscription(s, existing)
+		}
+		return
+	}
+	// hubs know feeds by their self link
+	topic := feed.Self
+	if topic == "" {
+		topic = dbFeed.Url
+	}
+	if found && existing.Hub == feed.Hub && existing.Topic == topic {
+		if existing.State == "pending" && (!existing.RequestedAt.Valid || time.Since(existing.RequestedAt.Time) > websubRetryAfter) {
+			requestWebsubSubscription(s, existing)
+		}
+		return
+	}
+	if found {
+		// the hub or topic changed, the new subscription gets its own id and
+		// secret
+		dropWebsubSubscription(s, existing)
+	}
+	secret, err := websub.NewSecret()
+	if err != nil {
+		fmt.Printf("Failed to create websub secret: %v\n", err)
+		return
+	}
+	sub, err := s.db.CreateWebsubSubscription(context.Background(), database.CreateWebsubSubscriptionParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		FeedID:    dbFeed.ID,
+		Hub:       feed.Hub,
+		Topic:     topic,
+		Secret:    secret,
+	})
+	if err != nil {
+		fmt.Printf("Failed to store websub subscription: %v\n", err)
+		return
+	}
+	requestWebsubSubscription(s, sub)
+}
+
+// requestWebsubSubscription sends a subscribe request, the hub confirms it
+// later through the callback served by serve
+func requestWebsubSubscription(s *state, sub database.WebsubSubscription) {
+	err := websub.Subscribe(context.Background(), s.client, websub.SubscribeRequest{
+		Hub:      sub.Hub,
+		Topic:    sub.Topic,
+		Callback: websubCallback(s, sub),
+		Secret:   sub.Secret,
+		Lease:    websubLease,
+	})
+	if markErr := s.db.MarkWebsubSubscriptionRequested(context.Background(), sub.ID); markErr != nil {
+		fmt.Printf("Failed to record websub request: %v\n", markErr)
+	}
+	if err != nil {
+		fmt.Printf("Failed to subscribe to %s: %v\n", sub.Hub, err)
+		return
+	}
+	fmt.Printf("📡 Asked %s to push %s\n", sub.Hub, sub.Topic)
+}
+
+// dropWebsubSubscription forgets a subscription and asks its hub to stop
+// pushing. The row goes first: the hub verifies the unsubscribe through the
+// callback, which only confirms it for ids we no longer know.
+func dropWebsubSubscription(s *state, sub database.WebsubSubscription) {
+	if err := s.db.DeleteWebsubSubscriptionForFeed(context.Background(), sub.FeedID); err != nil {
+		fmt.Printf("Failed to drop websub subscription: %v\n", err)
+		return
+	}
+	if sub.State == "denied" {
+		return
+	}
+	err := websub.Unsubscribe(context.Background(), s.client, websub.SubscribeRequest{
+		Hub:      sub.Hub,
+		Topic:    sub.Topic,
+		Callback: websubCallback(s, sub),
+	})
+	if err != nil {
+		fmt.Printf("Failed to unsubscribe from %s: %v\n", sub.Hub, err)
+		return
+	}
+	fmt.Printf("📡 Asked %s to stop pushing %s\n", sub.Hub, sub.Topic)
+}
+
+func websubCallback(s *state, sub database.WebsubSubscription) string {
+	return strings.TrimRight(s.configpointer.Websub_callback_url, "/") + "/" + sub.ID.String()
+}
+
+// renewWebsubLeases re-subscribes before hubs drop our subscriptions
+func renewWebsubLeases(s *state) {
+	if s.configpointer.Websub_callback_url == "" {
+		return
+	}
+	now := time.Now()
+	subs, err := s.db.GetWebsubSubscriptionsToRenew(context.Background(), database.GetWebsubSubscriptionsToRenewParams{
+		ExpiresBefore:   sql.NullTime{Time: now.Add(websubRenewBefore), Valid: true},
+		RequestedBefore: sql.NullTime{Time: now.Add(-websubRetryAfter), Valid: true},
+	})
+	if err != nil {
+		fmt.Printf("Failed to find websub leases to renew: %v\n", err)
+		return
+	}
+	for _, sub := range subs {
+		requestWebsubSubscription(s, sub)
+	}
+}
+
+func websubActive(s *state, feedID uuid.UUID) bool {
+	sub, err := s.db.GetWebsubSubscriptionForFeed(context.Background(), feedID)
+	if err != nil || sub.State != "active" {
+		return false
+	}
+	return !sub.LeaseExpiresAt.Valid || sub.LeaseExpiresAt.Time.After(time.Now())
+}
+
+func handlerServe(s *state, c command) error {
+	addr := s.configpointer.Websub_listen_addr
+	if len(c.arguments) > 0 {
+		addr = c.arguments[0]
+	}
+	if addr == "" {
+		addr = defaultServeAddr
+	}
+	if s.configpointer.Websub_callback_url == "" {
+		fmt.Println("websub_callback_url is not set, agg will not subscribe to any hubs")
+	}
+	handler := &websub.Handler{
+		Lookup: func(ctx context.Context, id string) (websub.Subscription, error) {
+			sub, err := lookupWebsubSubscription(ctx, s, id)
+			if err != nil {
+				return websub.Subscription{}, err
+			}
+			return websub.Subscription{ID: id, Topic: sub.Topic, Secret: sub.Secret}, nil
+		},
+		Verified: func(ctx context.Context, sub websub.Subscription, mode string, lease time.Duration) error {
+			return recordWebsubVerification(ctx, s, sub, mode, lease)
+		},
+		Deliver: func(ctx context.Context, sub websub.Subscription, contentType string, body []byte) error {
+			return ingestPushedFeed(ctx, s, sub, contentType, body)
+		},
+		MaxBodyBytes: s.client.MaxBodyBytes,
+	}
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	fmt.Printf("Listening for WebSub callbacks on %s\n", addr)
+	return server.ListenAndServe()
+}
+
+// lookupWebsubSubscription finds the subscription a callback belongs to,
+// denied ones are treated as gone
+func lookupWebsubSubscription(ctx context.Context, s *state, id string) (database.WebsubSubscription, error) {
+	subID, err := uuid.Parse(id)
+	if err != nil {
+		return database.WebsubSubscription{}, websub.ErrUnknownSubscription
+	}
+	sub, err := s.db.GetWebsubSubscription(ctx, subID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && sub.State == "denied") {
+		return sub, websub.ErrUnknownSubscription
+	}
+	return sub, err
+}
+
+func recordWebsubVerification(ctx context.Context, s *state, sub websub.Subscription, mode string, lease time.Duration) error {
+	subID, err := uuid.Parse(sub.ID)
+	if err != nil {
+		return err
+	}
+	if mode == "denied" {
+		fmt.Printf("⚠️  Hub denied the subscription to %s\n", sub.Topic)
+		return s.db.DenyWebsubSubscription(ctx, subID)
+	}
+	fmt.Printf("📡 Hub confirmed the subscription to %s for %s\n", sub.Topic, lease)
+	return s.db.ActivateWebsubSubscription(ctx, database.ActivateWebsubSubscriptionParams{
+		ID:             subID,
+		LeaseExpiresAt: sql.NullTime{Time: time.Now().Add(lease), Valid: lease > 0},
+	})
+}
+
+// ingestPushedFeed stores content a hub pushed the same way scrapeFeeds does
+func ingestPushedFeed(ctx context.Context, s *state, sub websub.Subscription, contentType string, body []byte) error {
+	stored, err := lookupWebsubSubscription(ctx, s, sub.ID)
+	if err != nil {
+		return err
+	}
+	dbFeed, err := s.db.GetFeedById(ctx, stored.FeedID)
+	if err != nil {
+		return err
+	}
+	feed, err := feedparse.Parse(contentType, body)
+	if err != nil {
+		fmt.Printf("Failed to parse content pushed for '%s': %v\n", dbFeed.Name, err)
+		return err
+	}
+	created := storePosts(s, dbFeed, feed.Items)
+	fmt.Printf("📨 %d new posts pushed for '%s'\n", created, dbFeed.Name)
 	return nil
 }
 
+// storeFeedMetadata refreshes what the channel says about itself
+func storeFeedMetadata(s *state, feedID uuid.UUID, feed *feedparse.Feed) {
+	var lastBuild sql.NullTime
+	if when, err := parsePubDate(feed.LastBuildDate); err == nil {
+		lastBuild = sql.NullTime{Time: when, Valid: true}
+	}
+	err := s.db.UpdateFeedMetadata(context.Background(), database.UpdateFeedMetadataParams{
+		ID:                 feedID,
+		ChannelTitle:       nullString(feed.Title),
+		ChannelDescription: nullString(feed.Description),
+		SiteLink:           nullString(feed.Link),
+		Language:           nullString(feed.Language),
+		ImageUrl:           nullString(feed.Image),
+		Generator:          nullString(feed.Generator),
+		LastBuildDate:      lastBuild,
+	})
+	if err != nil {
+		fmt.Printf("Failed to store feed metadata: %v\n", err)
+	}
+}
+
+func storeFeedSchedule(s *state, feedID uuid.UUID, schedule feedparse.Schedule) {
+	params := database.UpdateFeedScheduleParams{
+		ID: feedID,
+		UpdateIntervalSeconds: sql.NullInt32{
+			Int32: int32(schedule.Interval / time.Second),
+			Valid: schedule.Interval > 0,
+		},
+		SkipHours: []int32{},
+		SkipDays:  []int32{},
+	}
+	for _, hour := range schedule.SkipHours {
+		params.SkipHours = append(params.SkipHours, int32(hour))
+	}
+	for _, day := range schedule.SkipDays {
+		params.SkipDays = append(params.SkipDays, int32(day))
+	}
+	err := s.db.UpdateFeedSchedule(context.Background(), params)
+	if err != nil {
+		fmt.Printf("Failed to store feed schedule: %v\n", err)
+	}
+}
+
+func nullString(value string) sql.NullString {
+	value = strings.TrimSpace(value)
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+func storeAuthorsAndCategories(s *state, postID uuid.UUID, item feedparse.Item) {
+	for _, name := range item.Authors {
+		err := s.db.CreatePostAuthor(context.Background(), database.CreatePostAuthorParams{
+			ID:     uuid.New(),
+			PostID: postID,
+			Name:   name,
+		})
+		if err != nil {
+			fmt.Printf("Failed to insert author: %v\n", err)
+		}
+	}
+	for _, name := range item.Categories {
+		err := s.db.CreatePostCategory(context.Background(), database.CreatePostCategoryParams{
+			ID:     uuid.New(),
+			PostID: postID,
+			Name:   name,
+		})
+		if err != nil {
+			fmt.Printf("Failed to insert category: %v\n", err)
+		}
+	}
+}
+
+// storeFullText downloads a post's page and keeps the extracted article
+func storeFullText(s *state, postID uuid.UUID, link string) {
+	article, err := fetchFullText(context.Background(), s.client, link)
+	if err != nil {
+		fmt.Printf("Failed to extract full text from %s: %v\n", link, err)
+		return
+	}
+	err = s.db.UpdatePostFullText(context.Background(), database.UpdatePostFullTextParams{
+		ID:       postID,
+		FullText: sql.NullString{String: article, Valid: true},
+	})
+	if err != nil {
+		fmt.Printf("Failed to store full text: %v\n", err)
+	}
+}
+
+func fetchFullText(ctx context.Context, client *httpclient.Client, link string) (string, error) {
+	req, err := client.NewRequest(ctx, link)
+	if err != nil {
+		return "", fmt.Errorf("couldnt form request: %v", err)
+	}
+	resp, err := client.HTTP.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error recieving response: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", &statusError{status: resp.StatusCode}
+	}
+	body, err := client.ReadBody(resp)
+	if err != nil {
+		return "", fmt.Errorf("error reading body: %v", err)
+	}
+	return readability.Extract(resp.Request.URL.String(), body)
+}
+
+// storeAttachments saves the media files of a newly created post
+func storeAttachments(s *state, postID uuid.UUID, enclosures []feedparse.Enclosure) {
+	for _, enclosure := range enclosures {
+		seconds := int32(enclosure.Duration / time.Second)
+		err := s.db.CreateAttachment(context.Background(), database.CreateAttachmentParams{
+			ID:              uuid.New(),
+			CreatedAt:       time.Now(),
+			PostID:          postID,
+			Url:             enclosure.URL,
+			MimeType:        sql.NullString{String: enclosure.Type, Valid: enclosure.Type != ""},
+			Length:          sql.NullInt64{Int64: enclosure.Length, Valid: enclosure.Length > 0},
+			DurationSeconds: sql.NullInt32{Int32: seconds, Valid: seconds > 0},
+			ThumbnailUrl:    sql.NullString{String: enclosure.Thumbnail, Valid: enclosure.Thumbnail != ""},
+		})
+		if err != nil {
+			fmt.Printf("Failed to insert attachment: %v\n", err)
+		}
+	}
+}
+
+// recordFeedMove points a feed at its new permanent home, keeping the old url
+// around so follow and unfollow still find it
+func recordFeedMove(s *state, feed database.Feed, newURL string) {
+	// the stored credentials were given for the old host, moving would hand
+	// them to the new one on the next fetch
+	if feed.Credentials != nil && !sameHost(feed.Url, newURL) {
+		fmt.Printf("➡️  '%s' moved permanently to %s, a different host, keeping the old url so its credentials are not sent there. Re-add the feed to follow it.\n", feed.Name, newURL)
+		return
+	}
+	err := s.db.CreateFeedURLHistory(context.Background(), database.CreateFeedURLHistoryParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		FeedID:    feed.ID,
+		Url:       feed.Url,
+	})
+	if err != nil {
+		fmt.Printf("Failed to record previous feed url: %v\n", err)
+		return
+	}
+	err = s.db.UpdateFeedURL(context.Background(), database.UpdateFeedURLParams{
+		ID:  feed.ID,
+		Url: newURL,
+	})
+	if err != nil {
+		fmt.Printf("Failed to update feed url: %v\n", err)
+		return
+	}
+	fmt.Printf("➡️  '%s' moved permanently to %s\n", feed.Name, newURL)
+}
+
+func sameHost(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(ua.Host, ub.Host)
+}
+
+// recordFetchFailure stores the error and pushes the next attempt back,
+// honoring Retry-After when the server asked for a longer pause
+func recordFetchFailure(s *state, feed database.Feed, status int, fetchErr error) {
+	delay := failureBackoff(feed.ConsecutiveFailures + 1)
+	var statusErr *statusError
+	if errors.As(fetchErr, &statusErr) && statusErr.retryAfter > delay {
+		delay = statusErr.retryAfter
+	}
+	err := s.db.MarkFeedFetchFailed(context.Background(), database.MarkFeedFetchFailedParams{
+		ID:          feed.ID,
+		LastError:   sql.NullString{String: fetchErr.Error(), Valid: true},
+		LastStatus:  sql.NullInt32{Int32: int32(status), Valid: status != 0},
+		NextFetchAt: sql.NullTime{Time: time.Now().Add(delay), Valid: true},
+	})
+	if err != nil {
+		fmt.Printf("Failed to record fetch failure: %v\n", err)
+	}
+	failures := feed.ConsecutiveFailures + 1
+	if failures >= s.schedule.maxFailures {
+		if err := s.db.DisableFeed(context.Background(), feed.ID); err != nil {
+			fmt.Printf("Failed to disable feed: %v\n", err)
+			return
+		}
+		fmt.Printf("⛔ '%s' failed %d times in a row and was disabled, run 'gator feed enable %s' once it is fixed\n", feed.Name, failures, feed.Url)
+		return
+	}
+	fmt.Printf("⚠️  '%s' failed %d time(s) in a row, retrying in %s\n", feed.Name, failures, delay)
+}
+
 func handlerBrowse(s *state, c command, user database.User) error {
+	flags := flag.NewFlagSet("browse", flag.ContinueOnError)
+	author := flags.String("author", "", "only show posts by this author")
+	category := flags.String("category", "", "only show posts in this category")
+	if err := parseFlags(flags, c.arguments); err != nil {
+		return err
+	}
 	limit := int32(2) // Default limit
-	if len(c.arguments) > 0 {
-		if l, err := strconv.Atoi(c.arguments[0]); err == nil {
+	if flags.NArg() > 0 {
+		if l, err := strconv.Atoi(flags.Arg(0)); err == nil {
 			limit = int32(l)
 		}
 	}
 	userforposts := database.GetPostsForUserParams{
-		Name:  s.configpointer.Current_username,
-		Limit: limit,
+		Name:     s.configpointer.Current_username,
+		Author:   sql.NullString{String: *author, Valid: *author != ""},
+		Category: sql.NullString{String: *category, Valid: *category != ""},
+		Limit:    limit,
 	}
 	posts, err := s.db.GetPostsForUser(context.Background(), userforposts)
 	if err != nil {
 		return fmt.Errorf("failed to get posts: %w", err)
 	}
 
+	width := render.TerminalWidth()
 	for i, post := range posts {
 		fmt.Printf("🔖 [%d] %s\n", i+1, post.Title)
 		fmt.Printf("🔗 URL      : %s\n", post.Url)
-		fmt.Printf("📝 Summary  : %s\n", post.Description)
-		fmt.Printf("📅 Published: %s\n", post.PublishedAt.Time.Format(time.RFC1123))
+		fmt.Printf("📝 Summary  :\n%s\n", render.Text(post.Description, width))
+		fmt.Printf("📅 Published: %s\n", formatPublished(post))
+		authors, err := s.db.GetAuthorsForPost(context.Background(), post.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get authors: %w", err)
+		}
+		if len(authors) > 0 {
+			fmt.Printf("✍️  Authors  : %s\n", strings.Join(authors, ", "))
+		}
+		categories, err := s.db.GetCategoriesForPost(context.Background(), post.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get categories: %w", err)
+		}
+		if len(categories) > 0 {
+			fmt.Printf("🏷️  Tags     : %s\n", strings.Join(categories, ", "))
+		}
+		attachments, err := s.db.GetAttachmentsForPost(context.Background(), post.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get attachments: %w", err)
+		}
+		for _, attachment := range attachments {
+			printAttachment(attachment)
+		}
 		fmt.Println("────────────────────────────────────────────")
 	}
 	return nil
 }
 
-func parsePubDate(raw string) (time.Time, error) {
-	layouts := []string{
-		time.RFC1123Z,
-		time.RFC1123,
-		time.RFC822Z,
-		time.RFC822,
-		time.RFC3339,
+// handlerRead prints the stored full text of a post so it can be read offline
+func handlerRead(s *state, c command) error {
+	if len(c.arguments) < 1 {
+		return fmt.Errorf("this function requires a post url")
+	}
+	post, err := s.db.GetPostByUrl(context.Background(), c.arguments[0])
+	if err != nil {
+		return fmt.Errorf("couldnt find post: %v", err)
+	}
+	fmt.Printf("🔖 %s\n", post.Title)
+	fmt.Printf("🔗 URL      : %s\n", post.Url)
+	fmt.Printf("📅 Published: %s\n", formatPublished(post))
+	fmt.Println("────────────────────────────────────────────")
+	// prefer the extracted article, then what the feed sent
+	text := post.Description
+	if post.FullText.Valid {
+		text = post.FullText.String
+	} else if post.Content.Valid {
+		text = post.Content.String
+	}
+	fmt.Println(render.Text(text, render.TerminalWidth()))
+	return nil
+}
+
+// formatPublished marks dates that were guessed from the fetch time
+func formatPublished(post database.Post) string {
+	published := post.PublishedAt.Time.Format(time.RFC1123)
+	if post.PublishedAtEstimated {
+		published += " (estimated)"
+	}
+	return published
+}
+
+func printAttachment(attachment database.Attachment) {
+	fmt.Printf("🎧 Media    : %s\n", attachment.Url)
+	var details []string
+	if attachment.MimeType.Valid {
+		details = append(details, attachment.MimeType.String)
+	}
+	if attachment.Length.Valid {
+		details = append(details, formatBytes(attachment.Length.Int64))
+	}
+	if attachment.DurationSeconds.Valid {
+		details = append(details, (time.Duration(attachment.DurationSeconds.Int32) * time.Second).String())
+	}
+	if len(details) > 0 {
+		fmt.Printf("             %s\n", strings.Join(details, ", "))
+	}
+	if attachment.ThumbnailUrl.Valid {
+		fmt.Printf("🖼️  Thumbnail: %s\n", attachment.ThumbnailUrl.String)
+	}
+}
+
+// formatBytes prints a size using binary units
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func handlerDownload(s *state, c command, user database.User) error {
+	flags := flag.NewFlagSet("download", flag.ContinueOnError)
+	feedURL := flags.String("feed", "", "only download media from this feed")
+	limit := flags.Int("limit", 10, "maximum number of files to download")
+	if err := parseFlags(flags, c.arguments); err != nil {
+		return err
+	}
+	if flags.NArg() < 1 {
+		return fmt.Errorf("this function requires a directory")
+	}
+	dir := flags.Arg(0)
+
+	pending, err := s.db.GetPendingDownloadsForUser(context.Background(), database.GetPendingDownloadsForUserParams{
+		Name:    user.Name,
+		FeedUrl: sql.NullString{String: *feedURL, Valid: *feedURL != ""},
+		Limit:   int32(*limit),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get attachments: %w", err)
+	}
+	if len(pending) == 0 {
+		fmt.Println("Nothing new to download")
+		return nil
+	}
+
+	for _, attachment := range pending {
+		feedDir := filepath.Join(dir, download.SanitizeFilename(attachment.FeedName))
+		if err := os.MkdirAll(feedDir, 0755); err != nil {
+			return fmt.Errorf("couldnt create directory: %v", err)
+		}
+		name := attachment.PostTitle
+		ext := download.Extension(attachment.Url, attachment.MimeType.String)
+		dest := filepath.Join(feedDir, download.SanitizeFilename(name)+ext)
+		// never overwrite a finished file that is not ours
+		if _, err := os.Stat(dest); err == nil {
+			dest = filepath.Join(feedDir, download.SanitizeFilename(name)+"-"+attachment.ID.String()[:8]+ext)
+		}
+
+		fmt.Printf("⬇️  %s\n", attachment.Url)
+		result, err := download.Fetch(context.Background(), s.client, attachment.Url, dest, attachment.ID.String(), attachment.Length.Int64)
+		if err != nil {
+			fmt.Printf("Failed to download: %v\n", err)
+			continue
+		}
+		err = s.db.CreateDownload(context.Background(), database.CreateDownloadParams{
+			ID:           uuid.New(),
+			CreatedAt:    time.Now(),
+			AttachmentID: attachment.ID,
+			Path:         result.Path,
+			Size:         result.Size,
+			Sha256:       result.SHA256,
+		})
+		if err != nil {
+			return fmt.Errorf("downloaded %s but couldnt record it: %v", result.Path, err)
+		}
+		fmt.Printf("✅ Saved %s (%s, sha256 %s)\n", result.Path, formatBytes(result.Size), result.SHA256[:12])
 	}
+	return nil
+}
+
+// layouts tried by parsePubDate once normalizePubDate has removed the
+// weekday and turned zone names into offsets
+var pubDateLayouts = []string{
+	// rfc 822 and 1123 without the weekday, single digit days included
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04 -0700",
+	"2 Jan 06 15:04:05 -0700",
+	"2 Jan 06 15:04 -0700",
+	"2 January 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05",
+	"2 Jan 2006",
+	"Jan 2 2006 15:04:05 -0700",
+	"Jan 2 2006 15:04:05",
+	"Jan 2 2006",
+	"January 2 2006",
+	// iso 8601 and the w3c profile used by atom and dc:date
+	time.RFC3339,
+	"2006-01-02T15:04:05-0700",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	// unix date output
+	"Jan 2 15:04:05 -0700 2006",
+}
+
+// zone abbreviations feeds use in place of numeric offsets
+var pubDateZones = map[string]string{
+	"UT":   "+0000",
+	"UTC":  "+0000",
+	"GMT":  "+0000",
+	"Z":    "+0000",
+	"EST":  "-0500",
+	"EDT":  "-0400",
+	"CST":  "-0600",
+	"CDT":  "-0500",
+	"MST":  "-0700",
+	"MDT":  "-0600",
+	"PST":  "-0800",
+	"PDT":  "-0700",
+	"CET":  "+0100",
+	"CEST": "+0200",
+	"BST":  "+0100",
+	"IST":  "+0530",
+	"JST":  "+0900",
+	"AEST": "+1000",
+}
+
+var weekdayNames = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
 
-	for _, layout := range layouts {
-		if t, err := time.Parse(layout, raw); err == nil {
+func parsePubDate(raw string) (time.Time, error) {
+	normalized := normalizePubDate(raw)
+	if normalized == "" {
+		return time.Time{}, fmt.Errorf("missing date")
+	}
+	for _, layout := range pubDateLayouts {
+		if t, err := time.Parse(layout, normalized); err == nil {
 			return t, nil
 		}
 	}
 	return time.Time{}, fmt.Errorf("unknown date format: %s", raw)
 }
 
+// normalizePubDate irons out the common ways feeds break rfc 822: weekdays
+// (full, short or misspelled), stray commas, zone names and offsets written
+// as "GMT+0000"
+func normalizePubDate(raw string) string {
+	fields := strings.Fields(strings.ReplaceAll(raw, ",", " "))
+	if len(fields) == 0 {
+		return ""
+	}
+	if isWeekday(fields[0]) {
+		fields = fields[1:]
+	}
+	for i, field := range fields {
+		upper := strings.ToUpper(field)
+		if offset, ok := pubDateZones[upper]; ok {
+			fields[i] = offset
+			continue
+		}
+		// GMT+0000, UTC-05:00 and friends
+		for _, prefix := range []string{"GMT", "UTC"} {
+			if strings.HasPrefix(upper, prefix) && len(upper) > len(prefix) {
+				fields[i] = strings.ReplaceAll(field[len(prefix):], ":", "")
+			}
+		}
+		// numeric offsets with a colon only parse in iso layouts
+		if len(field) == 6 && (field[0] == '+' || field[0] == '-') && field[3] == ':' {
+			fields[i] = field[:3] + field[4:]
+		}
+	}
+	// a zone name followed by an offset, the offset is more specific
+	if n := len(fields); n >= 2 && isOffset(fields[n-1]) && isOffset(fields[n-2]) {
+		fields = append(fields[:n-2], fields[n-1])
+	}
+	return strings.Join(fields, " ")
+}
+
+func isWeekday(field string) bool {
+	field = strings.ToLower(strings.TrimSuffix(field, "."))
+	if len(field) < 3 {
+		return false
+	}
+	for _, day := range weekdayNames {
+		if strings.HasPrefix(day, field) || strings.HasPrefix(field, day[:3]) {
+			return true
+		}
+	}
+	return false
+}
+
+func isOffset(field string) bool {
+	if len(field) != 5 || (field[0] != '+' && field[0] != '-') {
+		return false
+	}
+	_, err := strconv.Atoi(field[1:])
+	return err == nil
+}
+
+// handlerValidate fetches a feed without storing anything and lists what
+// would keep it from producing posts
+func handlerValidate(s *state, c command) error {
+	if len(c.arguments) < 1 {
+		return fmt.Errorf("this function requires a url or file")
+	}
+	target := c.arguments[0]
+	// plain paths are read like file:// urls
+	if info, err := os.Stat(target); err == nil && !info.IsDir() {
+		abs, err := filepath.Abs(target)
+		if err != nil {
+			return err
+		}
+		target = (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}).String()
+	}
+	contentType, body, err := fetchRaw(context.Background(), s, target)
+	if err != nil {
+		return err
+	}
+	report := feedparse.Validate(contentType, body, func(raw string) error {
+		_, err := parsePubDate(raw)
+		return err
+	})
+	if report.Feed != nil {
+		fmt.Printf("%s feed '%s' with %d items\n", report.Format, report.Feed.Title, len(report.Feed.Items))
+	}
+	for _, problem := range report.Problems {
+		if problem.Line > 0 {
+			fmt.Printf("  line %d: %s: %s\n", problem.Line, problem.Severity, problem.Message)
+		} else {
+			fmt.Printf("  %s: %s\n", problem.Severity, problem.Message)
+		}
+	}
+	errorCount := report.Errors()
+	warnings := len(report.Problems) - errorCount
+	if len(report.Problems) == 0 {
+		fmt.Println("✅ No problems found")
+		return nil
+	}
+	fmt.Printf("%d error(s), %d warning(s)\n", errorCount, warnings)
+	return nil
+}
+
+// fetchRaw downloads a feed body without parsing it
+func fetchRaw(ctx context.Context, s *state, target string) (string, []byte, error) {
+	if localfeed.IsLocal(target) {
+		source := localfeed.Source{
+			Commands:     s.configpointer.Exec_commands,
+			MaxBodyBytes: s.client.MaxBodyBytes,
+			Timeout:      s.client.HTTP.Timeout,
+		}
+		local, err := source.Read(ctx, target)
+		if err != nil {
+			return "", nil, fmt.Errorf("couldnt read local feed: %v", err)
+		}
+		return local.ContentType, local.Body, nil
+	}
+	req, err := s.client.NewRequest(ctx, target)
+	if err != nil {
+		return "", nil, fmt.Errorf("couldnt form request: %v", err)
+	}
+	resp, err := s.client.HTTP.Do(req)
+	if err != nil {
+		return "", nil, fmt.Errorf("error recieving response: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, &statusError{status: resp.StatusCode}
+	}
+	body, err := s.client.ReadBody(resp)
+	if err != nil {
+		return "", nil, fmt.Errorf("error reading body: %v", err)
+	}
+	return resp.Header.Get("Content-Type"), body, nil
+}
+
 func handlerHelp(s *state, c command) error {
 	fmt.Println("Gator CLI Help")
 	fmt.Println()
@@ -480,13 +1924,22 @@ func handlerHelp(s *state, c command) error {
 	fmt.Println("  register <username>         - Register a new user")
 	fmt.Println("  login <username>            - Log in as a specific user")
 	fmt.Println("  users                       - List all registered users")
-	fmt.Println("  feeds                       - Show all feeds and their owners")
-	fmt.Println("  addfeed <name> <url>        - Add a new RSS feed and follow it")
+	fmt.Println("  feeds [--health]            - Show all feeds and their owners, or how well they are fetched")
+	fmt.Println("  addfeed [--auth kind:value] <name> <url> - Add a new feed (or a site that links to one) and follow it")
+	fmt.Println("                              url may also be file:///path/feed.xml or exec:<name> from exec_commands")
+	fmt.Println("  feed info <url>             - Show what a feed says about itself")
+	fmt.Println("  feed fulltext <url> <on|off> - Download and extract the full article for new posts")
+	fmt.Println("  feed enable <url>           - Resume fetching a feed that was disabled after repeated failures")
+	fmt.Println("  feed set-auth [--auth kind:value] <url> - Replace or, without --auth, remove a feed's credentials")
 	fmt.Println("  follow <feed-url>           - Follow an existing feed by URL")
 	fmt.Println("  following                   - List feeds the current user is following")
 	fmt.Println("  unfollow <feed-url>         - Unfollow a feed by URL")
-	fmt.Println("  browse [limit]              - Show recent posts from followed feeds (default: 2)")
+	fmt.Println("  browse [--author name] [--category name] [limit] - Show recent posts from followed feeds (default: 2)")
+	fmt.Println("  read <post-url>             - Show the full stored text of a post")
+	fmt.Println("  download [--feed url] [--limit n] <dir> - Download podcast and video files from followed feeds")
 	fmt.Println("  agg <duration>              - Continuously scrape feeds (e.g., '30s', '1m')")
+	fmt.Println("  serve [addr]                - Receive WebSub pushes for feeds that announce a hub")
+	fmt.Println("  validate <url-or-file>      - Check a feed for problems without subscribing to it")
 	fmt.Println("  help                        - Show this help message")
 	fmt.Println("Note: Make sure you're logged in for commands that require a user session.")
 	return nil
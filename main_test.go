@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Uttam1916/Gator/internal/credentials"
+	"github.com/Uttam1916/Gator/internal/httpclient"
+)
+
+const articlePage = `<html><body>
+<nav><a href="/">Home</a></nav>
+<article>
+<p>The full article text is only on the page, the feed just carries a short teaser of it.</p>
+<p>Read <a href="related">the related post</a> as well, it explains the rest in detail.</p>
+</article>
+</body></html>`
+
+func newTestClient(t *testing.T) *httpclient.Client {
+	t.Helper()
+	client, err := httpclient.New(httpclient.Options{UserAgent: "gator-test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestFetchFullText(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/p/1", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/posts/1/", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/posts/1/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") != "gator-test" {
+			t.Errorf("User-Agent = %q", r.Header.Get("User-Agent"))
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(articlePage))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	article, err := fetchFullText(context.Background(), newTestClient(t), srv.URL+"/p/1")
+	if err != nil {
+		t.Fatalf("fetchFullText: %v", err)
+	}
+	if !strings.Contains(article, "The full article text") {
+		t.Errorf("article text missing:\n%s", article)
+	}
+	if strings.Contains(article, "Home") {
+		t.Errorf("navigation was kept:\n%s", article)
+	}
+	// links resolve against the page we were redirected to
+	if want := `href="` + srv.URL + `/posts/1/related"`; !strings.Contains(article, want) {
+		t.Errorf("article is missing %s:\n%s", want, article)
+	}
+}
+
+func TestFetchFullTextErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gone":
+			http.NotFound(w, r)
+		default:
+			w.Write([]byte(`<html><body><p>Nothing here.</p></body></html>`))
+		}
+	}))
+	defer srv.Close()
+	client := newTestClient(t)
+
+	_, err := fetchFullText(context.Background(), client, srv.URL+"/gone")
+	var statusErr *statusError
+	if !errors.As(err, &statusErr) || statusErr.status != http.StatusNotFound {
+		t.Errorf("fetchFullText(/gone) = %v, want a 404 statusError", err)
+	}
+	if _, err := fetchFullText(context.Background(), client, srv.URL+"/empty"); err == nil {
+		t.Error("fetchFullText(/empty) succeeded on a page without an article")
+	}
+}
+
+func TestDiscoverFeedURLKeepsCredentialsOnHost(t *testing.T) {
+	var leaked string
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		leaked = r.Header.Get("Private-Token")
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(`<rss version="2.0"><channel><title>x</title></channel></rss>`))
+	}))
+	defer other.Close()
+	page := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Private-Token") != "secret" {
+			t.Errorf("page did not get the token")
+		}
+		http.Redirect(w, r, other.URL+"/feed.xml", http.StatusFound)
+	}))
+	defer page.Close()
+
+	auth := credentials.Credentials{Headers: map[string]string{"Private-Token": "secret"}}
+	if _, err := discoverFeedURL(context.Background(), newTestClient(t), page.URL, auth); err != nil {
+		t.Fatalf("discoverFeedURL: %v", err)
+	}
+	if leaked != "" {
+		t.Errorf("token was sent to %s after a redirect", other.URL)
+	}
+}
+
+func TestParsePubDate(t *testing.T) {
+	want := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		raw  string
+		want time.Time
+	}{
+		{"Mon, 02 Jan 2006 15:04:05 GMT", want},
+		{"Mon, 2 Jan 2006 15:04:05 GMT+0000", want},
+		{"Mon, 2 Jan 2006 15:04:05 UTC+00:00", want},
+		{"Mon, 02 Jan 2006 15:04:05 +0000", want},
+		{"Mon, 02 Jan 2006 10:04:05 EST", want},
+		{"Mon, 02 Jan 2006 16:04:05 +01:00", want},
+		{"Monday, 2 Jan 2006 15:04:05 GMT", want},
+		{"Mon., 2 Jan 2006 15:04:05 Z", want},
+		{"Tue, 2 Jan 2006 15:04:05 GMT", want},
+		{"2 Jan 2006 15:04:05 GMT +0000", want},
+		{"02 Jan 06 15:04:05 +0000", want},
+		{"  Mon, 02 Jan 2006 15:04:05 GMT  ", want},
+		{"Mon, 02 Jan 2006 15:04 GMT", want.Truncate(time.Minute)},
+		{"2 January 2006 15:04:05 +0000", want},
+		{"2006-01-02T15:04:05Z", want},
+		{"2006-01-02T17:04:05+02:00", want},
+		{"2006-01-02T15:04:05", want},
+		{"2006-01-02 15:04:05", want},
+		{"2006-01-02", want.Truncate(24 * time.Hour)},
+		{"Jan 2 15:04:05 +0000 2006", want},
+	}
+	for _, tt := range tests {
+		got, err := parsePubDate(tt.raw)
+		if err != nil {
+			t.Errorf("parsePubDate(%q): %v", tt.raw, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parsePubDate(%q) = %s, want %s", tt.raw, got.UTC(), tt.want)
+		}
+	}
+}
+
+func TestParsePubDateFailures(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"", "missing date"},
+		{"   ", "missing date"},
+		{",", "missing date"},
+		{"sometime last week", "unknown date format"},
+		{"Mon, 32 Jan 2006 15:04:05 GMT", "unknown date format"},
+	}
+	for _, tt := range tests {
+		_, err := parsePubDate(tt.raw)
+		if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+			t.Errorf("parsePubDate(%q) = %v, want %q", tt.raw, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+-- name: CreateAttachment :exec
+INSERT INTO attachments (
+    id,
+    created_at,
+    post_id,
+    url,
+    mime_type,
+    length,
+    duration_seconds,
+    thumbnail_url
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+)
+ON CONFLICT (post_id, url) DO NOTHING;
+
+-- name: GetAttachmentsForPost :many
+SELECT * FROM attachments WHERE post_id = $1 ORDER BY created_at;
@@ -0,0 +1,21 @@
+-- name: GetPendingDownloadsForUser :many
+SELECT
+    attachments.*,
+    posts.title AS post_title,
+    posts.published_at,
+    feed.name AS feed_name
+FROM attachments
+JOIN posts ON posts.id = attachments.post_id
+JOIN feed ON feed.id = posts.feed_id
+JOIN feedfollows ON feedfollows.feed_id = feed.id
+JOIN users ON users.id = feedfollows.user_id
+LEFT JOIN downloads ON downloads.attachment_id = attachments.id
+WHERE users.name = sqlc.arg('name')
+  AND downloads.id IS NULL
+  AND (sqlc.narg('feed_url')::text IS NULL OR feed.url = sqlc.narg('feed_url'))
+ORDER BY posts.published_at DESC
+LIMIT sqlc.arg('limit');
+
+-- name: CreateDownload :exec
+INSERT INTO downloads (id, created_at, attachment_id, path, size, sha256)
+VALUES ($1, $2, $3, $4, $5, $6);
@@ -11,7 +11,9 @@ RETURNING *;
 
 -- name: ReturnAllFeedsWithUsers :many
 SELECT 
-    f.id, f.created_at, f.updated_at, f.name, f.url, f.user_id, u.name AS username
+    f.id, f.created_at, f.updated_at, f.name, f.url, f.user_id,
+    f.channel_title, f.site_link, f.language,
+    f.credentials IS NOT NULL AS has_credentials, f.disabled_at, u.name AS username
 FROM 
     feed f
 JOIN 
@@ -38,7 +40,10 @@ JOIN users ON inserted.user_id = users.id
 JOIN feed ON inserted.feed_id = feed.id;
 
 -- name: GetFeedIdFromUrl :one
-SELECT feed.id FROM feed WHERE url=$1;
+SELECT feed.id FROM feed WHERE feed.url=$1
+UNION
+SELECT feed_url_history.feed_id FROM feed_url_history WHERE feed_url_history.url=$1
+LIMIT 1;
 
 -- name: GetFeedFollowsForUser :many
 SELECT
@@ -61,12 +66,84 @@ USING users, feed
 WHERE feedfollows.user_id = users.id
   AND feedfollows.feed_id = feed.id
   AND users.name = $1
-  AND feed.url = $2;
+  AND (feed.url = $2
+    OR feed.id IN (SELECT feed_url_history.feed_id FROM feed_url_history WHERE feed_url_history.url = $2));
 
 -- name: MarkFetchedFeed :exec
 
-UPDATE feed SET lastfetched_at=now(), updated_at=now() WHERE id=$1;
+UPDATE feed SET last_fetched_at=now(), updated_at=now() WHERE id=$1;
+
+-- name: GetFeedById :one
+SELECT * FROM feed WHERE id=$1;
 
 -- name: GetNextFeed :one
-SELECT * FROM feed ORDER BY lastfetched_at NULLS FIRST LIMIT 1;
+SELECT * FROM feed
+WHERE disabled_at IS NULL
+  AND (next_fetch_at IS NULL OR next_fetch_at <= now())
+ORDER BY next_fetch_at NULLS FIRST, last_fetched_at NULLS FIRST
+LIMIT 1;
+
+
+-- name: UpdateFeedCacheHeaders :exec
+UPDATE feed SET etag=$2, last_modified=$3 WHERE id=$1;
+
+-- name: MarkFeedFetchSucceeded :exec
+UPDATE feed SET consecutive_failures=0, last_error=NULL, last_status=$2, next_fetch_at=$3 WHERE id=$1;
+
+-- name: MarkFeedFetchFailed :exec
+UPDATE feed SET consecutive_failures=consecutive_failures+1, last_error=$2, last_status=$3, next_fetch_at=$4 WHERE id=$1;
+
+-- name: AddFeedFetchedItems :exec
+UPDATE feed SET fetch_count=fetch_count+1, item_count=item_count+$2 WHERE id=$1;
+
+-- name: AddFeedNewPosts :exec
+UPDATE feed SET new_post_count=new_post_count+$2, last_post_at=now() WHERE id=$1;
+
+-- name: DisableFeed :exec
+UPDATE feed SET disabled_at=now(), updated_at=now() WHERE id=$1;
+
+-- name: EnableFeed :execrows
+UPDATE feed SET disabled_at=NULL, consecutive_failures=0, next_fetch_at=NULL, updated_at=now() WHERE url=$1;
+
+-- name: GetFeedHealth :many
+SELECT
+    f.id, f.name, f.url, f.last_fetched_at, f.last_status, f.last_error,
+    f.consecutive_failures, f.fetch_count, f.item_count, f.new_post_count,
+    f.last_post_at, f.next_fetch_at, f.disabled_at
+FROM feed f
+ORDER BY f.disabled_at IS NULL, f.consecutive_failures DESC, f.name;
+
+-- name: UpdateFeedURL :exec
+UPDATE feed SET url=$2, updated_at=now() WHERE id=$1;
+
+-- name: CreateFeedURLHistory :exec
+INSERT INTO feed_url_history (id, created_at, feed_id, url)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (url) DO NOTHING;
+
+-- name: SetFeedFetchFullText :execrows
+UPDATE feed SET fetch_full_text=$2, updated_at=now() WHERE url=$1;
+
+-- name: SetFeedCredentials :execrows
+UPDATE feed SET credentials=$2, updated_at=now() WHERE url=$1;
+
+-- name: UpdateFeedMetadata :exec
+UPDATE feed SET
+    channel_title=$2,
+    channel_description=$3,
+    site_link=$4,
+    language=$5,
+    image_url=$6,
+    generator=$7,
+    last_build_date=$8,
+    updated_at=now()
+WHERE id=$1;
+
+-- name: UpdateFeedSchedule :exec
+UPDATE feed SET update_interval_seconds=$2, skip_hours=$3, skip_days=$4 WHERE id=$1;
 
+-- name: GetFeedByUrl :one
+SELECT * FROM feed
+WHERE feed.url=$1
+   OR feed.id IN (SELECT feed_url_history.feed_id FROM feed_url_history WHERE feed_url_history.url=$1)
+LIMIT 1;
@@ -0,0 +1,15 @@
+-- name: CreatePostAuthor :exec
+INSERT INTO post_authors (id, post_id, name)
+VALUES ($1, $2, $3)
+ON CONFLICT (post_id, name) DO NOTHING;
+
+-- name: CreatePostCategory :exec
+INSERT INTO post_categories (id, post_id, name)
+VALUES ($1, $2, $3)
+ON CONFLICT (post_id, name) DO NOTHING;
+
+-- name: GetAuthorsForPost :many
+SELECT name FROM post_authors WHERE post_id = $1 ORDER BY name;
+
+-- name: GetCategoriesForPost :many
+SELECT name FROM post_categories WHERE post_id = $1 ORDER BY name;
@@ -1,4 +1,4 @@
--- name: CreatePost :exec
+-- name: CreatePost :execrows
 INSERT INTO posts (
     id,
     created_at,
@@ -7,10 +7,18 @@ INSERT INTO posts (
     url,
     description,
     published_at,
-    feed_id
-) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8
-);
+    feed_id,
+    content,
+    guid,
+    published_at_estimated
+)
+SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+-- posts stored before guids existed got their url as guid, which Key() does
+-- not always produce, so match those on the url
+WHERE NOT EXISTS (
+    SELECT 1 FROM posts WHERE feed_id = $8 AND url = $5 AND guid = url
+)
+ON CONFLICT (feed_id, guid) DO NOTHING;
 -- name: GetPostsForUser :many
 
 SELECT
@@ -18,6 +26,20 @@ SELECT
 FROM posts
 JOIN feedfollows ON posts.feed_id = feedfollows.feed_id
 JOIN users ON feedfollows.user_id = users.id
-WHERE users.name = $1
+WHERE users.name = sqlc.arg('name')
+  AND (sqlc.narg('author')::text IS NULL OR EXISTS (
+    SELECT 1 FROM post_authors
+    WHERE post_authors.post_id = posts.id AND lower(post_authors.name) = lower(sqlc.narg('author'))
+  ))
+  AND (sqlc.narg('category')::text IS NULL OR EXISTS (
+    SELECT 1 FROM post_categories
+    WHERE post_categories.post_id = posts.id AND lower(post_categories.name) = lower(sqlc.narg('category'))
+  ))
 ORDER BY posts.published_at DESC
-LIMIT $2;
+LIMIT sqlc.arg('limit');
+
+-- name: GetPostByUrl :one
+SELECT * FROM posts WHERE url = $1 LIMIT 1;
+
+-- name: UpdatePostFullText :exec
+UPDATE posts SET full_text=$2, updated_at=now() WHERE id=$1;
@@ -0,0 +1,34 @@
+-- name: CreateWebsubSubscription :one
+INSERT INTO websub_subscriptions (id, created_at, updated_at, feed_id, hub, topic, secret)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (feed_id) DO UPDATE SET
+    hub = EXCLUDED.hub,
+    topic = EXCLUDED.topic,
+    state = 'pending',
+    lease_expires_at = NULL,
+    updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: GetWebsubSubscription :one
+SELECT * FROM websub_subscriptions WHERE id = $1;
+
+-- name: GetWebsubSubscriptionForFeed :one
+SELECT * FROM websub_subscriptions WHERE feed_id = $1;
+
+-- name: MarkWebsubSubscriptionRequested :exec
+UPDATE websub_subscriptions SET requested_at = now() WHERE id = $1;
+
+-- name: ActivateWebsubSubscription :exec
+UPDATE websub_subscriptions SET state = 'active', lease_expires_at = $2, updated_at = now() WHERE id = $1;
+
+-- name: DenyWebsubSubscription :exec
+UPDATE websub_subscriptions SET state = 'denied', lease_expires_at = NULL, updated_at = now() WHERE id = $1;
+
+-- name: DeleteWebsubSubscriptionForFeed :exec
+DELETE FROM websub_subscriptions WHERE feed_id = $1;
+
+-- name: GetWebsubSubscriptionsToRenew :many
+SELECT * FROM websub_subscriptions
+WHERE state = 'active'
+  AND lease_expires_at <= sqlc.arg('expires_before')
+  AND (requested_at IS NULL OR requested_at <= sqlc.arg('requested_before'));
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE feed ADD COLUMN etag TEXT;
+ALTER TABLE feed ADD COLUMN last_modified TEXT;
+
+-- +goose Down
+ALTER TABLE feed DROP COLUMN etag;
+ALTER TABLE feed DROP COLUMN last_modified;
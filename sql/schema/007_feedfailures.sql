@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE feed ADD COLUMN consecutive_failures INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE feed ADD COLUMN last_error TEXT;
+ALTER TABLE feed ADD COLUMN last_status INTEGER;
+ALTER TABLE feed ADD COLUMN next_fetch_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE feed DROP COLUMN consecutive_failures;
+ALTER TABLE feed DROP COLUMN last_error;
+ALTER TABLE feed DROP COLUMN last_status;
+ALTER TABLE feed DROP COLUMN next_fetch_at;
@@ -0,0 +1,10 @@
+-- +goose Up
+CREATE TABLE feed_url_history (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    feed_id UUID NOT NULL REFERENCES feed(id) ON DELETE CASCADE,
+    url TEXT NOT NULL UNIQUE
+);
+
+-- +goose Down
+DROP TABLE feed_url_history;
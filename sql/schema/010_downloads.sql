@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE downloads (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    attachment_id UUID NOT NULL UNIQUE REFERENCES attachments(id) ON DELETE CASCADE,
+    path TEXT NOT NULL,
+    size BIGINT NOT NULL,
+    sha256 TEXT NOT NULL
+);
+
+-- +goose Down
+DROP TABLE downloads;
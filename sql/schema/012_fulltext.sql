@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE feed ADD COLUMN fetch_full_text BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE posts ADD COLUMN full_text TEXT;
+
+-- +goose Down
+ALTER TABLE feed DROP COLUMN fetch_full_text;
+ALTER TABLE posts DROP COLUMN full_text;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE posts ADD COLUMN published_at_estimated BOOLEAN NOT NULL DEFAULT false;
+
+-- +goose Down
+ALTER TABLE posts DROP COLUMN published_at_estimated;
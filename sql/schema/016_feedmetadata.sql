@@ -0,0 +1,17 @@
+-- +goose Up
+ALTER TABLE feed ADD COLUMN channel_title TEXT;
+ALTER TABLE feed ADD COLUMN channel_description TEXT;
+ALTER TABLE feed ADD COLUMN site_link TEXT;
+ALTER TABLE feed ADD COLUMN language TEXT;
+ALTER TABLE feed ADD COLUMN image_url TEXT;
+ALTER TABLE feed ADD COLUMN generator TEXT;
+ALTER TABLE feed ADD COLUMN last_build_date TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE feed DROP COLUMN last_build_date;
+ALTER TABLE feed DROP COLUMN generator;
+ALTER TABLE feed DROP COLUMN image_url;
+ALTER TABLE feed DROP COLUMN language;
+ALTER TABLE feed DROP COLUMN site_link;
+ALTER TABLE feed DROP COLUMN channel_description;
+ALTER TABLE feed DROP COLUMN channel_title;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE feed ADD COLUMN update_interval_seconds INT;
+ALTER TABLE feed ADD COLUMN skip_hours INT[] NOT NULL DEFAULT '{}';
+ALTER TABLE feed ADD COLUMN skip_days INT[] NOT NULL DEFAULT '{}';
+
+-- +goose Down
+ALTER TABLE feed DROP COLUMN skip_days;
+ALTER TABLE feed DROP COLUMN skip_hours;
+ALTER TABLE feed DROP COLUMN update_interval_seconds;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE feed ADD COLUMN credentials BYTEA;
+
+-- +goose Down
+ALTER TABLE feed DROP COLUMN credentials;
@@ -0,0 +1,18 @@
+-- +goose Up
+ALTER TABLE feed ADD COLUMN disabled_at TIMESTAMPTZ;
+ALTER TABLE feed ADD COLUMN fetch_count INT NOT NULL DEFAULT 0;
+ALTER TABLE feed ADD COLUMN new_post_count INT NOT NULL DEFAULT 0;
+ALTER TABLE feed ADD COLUMN item_count INT NOT NULL DEFAULT 0;
+-- lastfetched_at was a TIME without a date, those values cannot be converted
+ALTER TABLE feed RENAME COLUMN lastfetched_at TO last_fetched_at;
+ALTER TABLE feed ALTER COLUMN last_fetched_at TYPE TIMESTAMPTZ USING NULL;
+ALTER TABLE feed ADD COLUMN last_post_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE feed DROP COLUMN last_post_at;
+ALTER TABLE feed ALTER COLUMN last_fetched_at TYPE TIME USING last_fetched_at::time;
+ALTER TABLE feed RENAME COLUMN last_fetched_at TO lastfetched_at;
+ALTER TABLE feed DROP COLUMN item_count;
+ALTER TABLE feed DROP COLUMN new_post_count;
+ALTER TABLE feed DROP COLUMN fetch_count;
+ALTER TABLE feed DROP COLUMN disabled_at;